@@ -4,16 +4,20 @@ package agent
 type Type string
 
 const (
-	TypeClaude  Type = "claude"
-	TypeCopilot Type = "copilot"
-	TypeCodex   Type = "codex"
+	TypeClaude   Type = "claude"
+	TypeCopilot  Type = "copilot"
+	TypeCodex    Type = "codex"
+	TypeGemini   Type = "gemini"
+	TypeAider    Type = "aider"
+	TypeOpenCode Type = "opencode"
 )
 
 // Status represents the status of a coding agent instance.
 type Status struct {
-	State       string // Idle, Running, Waiting, Unknown
-	Mode        string // plan mode, accept edits, or empty
-	Description string // Additional description (e.g., time elapsed)
+	State       string `json:"state"`                 // Idle, Running, Waiting, Unknown
+	Mode        string `json:"mode,omitempty"`        // plan mode, accept edits, or empty
+	Description string `json:"description,omitempty"` // Additional description (e.g., time elapsed)
+	Message     string `json:"message,omitempty"`     // Last message from the agent (e.g., last assistant message), if reported
 }
 
 // Status state constants
@@ -28,6 +32,13 @@ const (
 const (
 	ModePlan        = "plan mode"
 	ModeAcceptEdits = "accept edits"
+
+	// Aider chat modes
+	ModeCode      = "code mode"
+	ModeArchitect = "architect mode"
+	ModeAsk       = "ask mode"
+	ModeHelp      = "help mode"
+	ModeContext   = "context mode"
 )
 
 // Detector defines the interface for detecting and parsing coding agents.
@@ -38,6 +49,14 @@ type Detector interface {
 	MayBeProcess(currentCommand string) bool
 	ExtractSummary(title string) string
 	ParseStatus(content string) Status
+	Score(s Signals) Score
+}
+
+// ContentDetector is implemented by detectors that can recognise their agent by its on-screen chrome
+// (footer hints, prompt box, welcome banner) alone, e.g., when the pane title is unavailable or
+// the agent runs inside docker, ssh or devcontainer.
+type ContentDetector interface {
+	MayBeContent(content string) bool
 }
 
 // All registered detectors
@@ -45,15 +64,16 @@ var detectors = []Detector{
 	&ClaudeAgent{},
 	&CopilotAgent{},
 	&CodexAgent{},
+	&GeminiAgent{},
+	&AiderAgent{},
+	&OpenCodeAgent{},
 }
 
 // Detect checks if a pane might be running a coding agent.
 // Returns the detected agent or nil if no agent is detected.
 func Detect(title, currentCommand string) Detector {
-	for _, d := range detectors {
-		if d.MayBeTitle(title) && d.MayBeProcess(currentCommand) {
-			return d
-		}
-	}
-	return nil
+	return DetectSignals(Signals{
+		Title:          title,
+		CurrentCommand: currentCommand,
+	})
 }
@@ -52,6 +52,36 @@ func TestDetect(t *testing.T) {
 			currentCommand: "codex",
 			wantType:       TypeCodex,
 		},
+		{
+			name:           "Gemini CLI",
+			title:          "Gemini - tcmux",
+			currentCommand: "gemini",
+			wantType:       TypeGemini,
+		},
+		{
+			name:           "Gemini CLI with node",
+			title:          "◇  Ready (tcmux)",
+			currentCommand: "node",
+			wantType:       TypeGemini,
+		},
+		{
+			name:           "Aider",
+			title:          "tailor.local",
+			currentCommand: "aider",
+			wantType:       TypeAider,
+		},
+		{
+			name:           "Aider with python",
+			title:          "tailor.local",
+			currentCommand: "python3",
+			wantType:       TypeAider,
+		},
+		{
+			name:           "OpenCode",
+			title:          "OC | Fix login bug",
+			currentCommand: "opencode",
+			wantType:       TypeOpenCode,
+		},
 		{
 			name:           "Normal shell",
 			title:          "zsh",
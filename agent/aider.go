@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// aiderPythonPattern matches Python interpreter process names (e.g., "python", "python3", "python3.12").
+// Aider installed via pip/pipx runs as a Python interpreter.
+var aiderPythonPattern = regexp.MustCompile(`^python(\d+(\.\d+)?)?$`)
+
+// AiderAgent detects and parses Aider instances.
+type AiderAgent struct{}
+
+func (a *AiderAgent) Type() Type {
+	return TypeAider
+}
+
+func (a *AiderAgent) Icon() string {
+	return "◈"
+}
+
+// MayBeTitle checks if the pane title may indicate an Aider instance.
+// Aider does not set the pane title, so any title is accepted.
+func (a *AiderAgent) MayBeTitle(title string) bool {
+	return true
+}
+
+// MayBeProcess checks if the current command may be an Aider process.
+// Aider runs as "aider" or as a Python interpreter ("python", "python3", ...).
+func (a *AiderAgent) MayBeProcess(currentCommand string) bool {
+	return currentCommand == "aider" || aiderPythonPattern.MatchString(currentCommand)
+}
+
+// MayBeArgs checks if the command line runs Aider.
+// e.g., "aider", "python3 .../bin/aider", "python -m aider".
+func (a *AiderAgent) MayBeArgs(args []string) bool {
+	return programName(args) == "aider"
+}
+
+// Score returns the detection confidence for Aider.
+// A Python interpreter may be anything, so its screen must show Aider's chrome once captured.
+func (a *AiderAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs,
+		func(c string) bool { return c == "aider" },
+		func(c string) bool { return aiderPythonPattern.MatchString(c) })
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	} else if process == scoreProcessShared && s.Content != "" {
+		return Score{}
+	}
+	return score
+}
+
+// ExtractSummary extracts the task summary from the pane title.
+// Aider does not set the pane title, so there is no summary.
+func (a *AiderAgent) ExtractSummary(title string) string {
+	return ""
+}
+
+// ParseStatus parses the pane content and determines the Aider status.
+func (a *AiderAgent) ParseStatus(content string) Status {
+	return parseAiderStatus(content)
+}
+
+// MayBeContent checks if the pane content contains lines only Aider prints.
+func (a *AiderAgent) MayBeContent(content string) bool {
+	return aiderSignaturePattern.MatchString(content) ||
+		strings.Contains(content, aiderConfirmMarker)
+}
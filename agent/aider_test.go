@@ -0,0 +1,191 @@
+package agent
+
+import "testing"
+
+func TestAiderAgent_MayBeProcess(t *testing.T) {
+	agent := &AiderAgent{}
+	tests := []struct {
+		name           string
+		currentCommand string
+		want           bool
+	}{
+		{"Aider binary", "aider", true},
+		{"Python", "python", true},
+		{"Python 3", "python3", true},
+		{"Python 3 with minor version", "python3.12", true},
+		{"Node process", "node", false},
+		{"Pythonista is not Python", "pythonista", false},
+		{"Zsh shell", "zsh", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.MayBeProcess(tt.currentCommand)
+			if got != tt.want {
+				t.Errorf("AiderAgent.MayBeProcess(%q) = %v, want %v", tt.currentCommand, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAiderAgent_Score(t *testing.T) {
+	agent := &AiderAgent{}
+	tests := []struct {
+		name           string
+		currentCommand string
+		content        string
+		want           bool
+	}{
+		{
+			name:           "Aider binary needs no signature",
+			currentCommand: "aider",
+			content:        "> ",
+			want:           true,
+		},
+		{
+			name:           "Python with Aider banner",
+			currentCommand: "python3",
+			content: `Aider v0.86.1
+Main model: anthropic/claude-sonnet-4-20250514 with diff edit format
+Git repo: .git with 42 files
+Repo-map: using 4096 tokens, auto refresh
+> `,
+			want: true,
+		},
+		{
+			name:           "Python with token report",
+			currentCommand: "python3",
+			content: `Applied edit to main.py
+Tokens: 2.1k sent, 312 received. Cost: $0.01 message, $0.03 session.
+> `,
+			want: true,
+		},
+		{
+			name:           "Python with named mode prompt",
+			currentCommand: "python",
+			content:        "architect> ",
+			want:           true,
+		},
+		{
+			name:           "Python before capture",
+			currentCommand: "python3",
+			content:        "",
+			want:           true,
+		},
+		{
+			name:           "Python REPL",
+			currentCommand: "python3",
+			content: `Python 3.12.1 (main, Dec  7 2023, 20:45:44)
+>>> `,
+			want: false,
+		},
+		{
+			name:           "Python script output",
+			currentCommand: "python",
+			content:        "Serving HTTP on 0.0.0.0 port 8000 ...",
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.Score(Signals{CurrentCommand: tt.currentCommand, Content: tt.content}).Total() > 0
+			if got != tt.want {
+				t.Errorf("AiderAgent.Score(%q, %q) > 0 = %v, want %v", tt.currentCommand, tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAiderAgent_ParseStatus(t *testing.T) {
+	agent := &AiderAgent{}
+	tests := []struct {
+		name      string
+		content   string
+		wantState string
+		wantMode  string
+	}{
+		{
+			name: "Idle with default prompt",
+			content: `Tokens: 2.1k sent, 312 received. Cost: $0.01 message, $0.03 session.
+
+main.py
+> `,
+			wantState: StateIdle,
+			wantMode:  ModeCode,
+		},
+		{
+			name: "Idle with edit format prompt",
+			content: `main.py
+diff> `,
+			wantState: StateIdle,
+			wantMode:  ModeCode,
+		},
+		{
+			name: "Idle in architect mode",
+			content: `main.py
+architect> `,
+			wantState: StateIdle,
+			wantMode:  ModeArchitect,
+		},
+		{
+			name: "Idle in ask mode with multiline",
+			content: `main.py
+ask multi> `,
+			wantState: StateIdle,
+			wantMode:  ModeAsk,
+		},
+		{
+			name: "Waiting with add file confirmation",
+			content: `ask> how does parseStatus work?
+
+agent/status.go
+Add file to the chat? (Y)es/(N)o/(A)ll/(S)kip all/(D)on't ask again [Yes]:`,
+			wantState: StateWaiting,
+			wantMode:  ModeAsk,
+		},
+		{
+			name: "Waiting with shell command confirmation",
+			content: `go test ./...
+Run shell command? (Y)es/(N)o/(D)on't ask again [Yes]:`,
+			wantState: StateWaiting,
+		},
+		{
+			name: "Running while waiting for the LLM",
+			content: `architect> add a Gemini detector
+
+░█        Waiting for anthropic/claude-sonnet-4-20250514`,
+			wantState: StateRunning,
+			wantMode:  ModeArchitect,
+		},
+		{
+			name: "Running while streaming output",
+			content: `> add a Gemini detector
+
+I'll add a new detector in agent/gemini.go.
+
+agent/gemini.go`,
+			wantState: StateRunning,
+			wantMode:  ModeCode,
+		},
+		{
+			name: "Unknown state",
+			content: `Some random output
+without any recognizable pattern`,
+			wantState: StateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ParseStatus(tt.content)
+			if got.State != tt.wantState {
+				t.Errorf("AiderAgent.ParseStatus().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Mode != tt.wantMode {
+				t.Errorf("AiderAgent.ParseStatus().Mode = %q, want %q", got.Mode, tt.wantMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ArgsDetector is implemented by detectors that can recognise their process by its command line.
+// It is used when the process tree of a pane is available.
+type ArgsDetector interface {
+	MayBeArgs(args []string) bool
+}
+
+// interpreterPattern matches runtimes that run coding agents as scripts.
+var interpreterPattern = regexp.MustCompile(`^(node|bun|deno|python(\d+(\.\d+)?)?)$`)
+
+// IsInterpreter checks if the command is a script runtime (e.g., "node", "python3").
+// The process name of an interpreter alone does not tell which program it runs.
+func IsInterpreter(command string) bool {
+	return interpreterPattern.MatchString(command)
+}
+
+// DetectArgs checks if a command line runs a coding agent.
+// Returns the detected agent or nil if no agent is detected.
+func DetectArgs(args []string) Detector {
+	if len(args) == 0 {
+		return nil
+	}
+	for _, d := range detectors {
+		if ad, ok := d.(ArgsDetector); ok && ad.MayBeArgs(args) {
+			return d
+		}
+	}
+	return nil
+}
+
+// programPath returns the path of the program a command line runs.
+// For interpreters, it is the script being run (or the module of "python -m").
+func programPath(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if !IsInterpreter(filepath.Base(args[0])) {
+		return args[0]
+	}
+	for i := 1; i < len(args); i++ {
+		if args[i] == "-m" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		return args[i]
+	}
+	return args[0]
+}
+
+// programName returns the base name of the program a command line runs.
+func programName(args []string) string {
+	return filepath.Base(programPath(args))
+}
@@ -0,0 +1,49 @@
+package agent
+
+import "testing"
+
+func TestDetectArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantType Type
+		wantNil  bool
+	}{
+		{"Claude binary", []string{"claude", "--continue"}, TypeClaude, false},
+		{"Claude via node", []string{"node", "/usr/lib/node_modules/@anthropic-ai/claude-code/cli.js"}, TypeClaude, false},
+		{"Claude via npx", []string{"node", "/home/u/.npm/_npx/1234/node_modules/.bin/claude"}, TypeClaude, false},
+		{"Claude Native Install", []string{"/home/u/.local/share/claude/versions/2.1.34"}, TypeClaude, false},
+		{"Claude via nix run", []string{"/nix/store/abc-claude-code-2.1.34/bin/claude"}, TypeClaude, false},
+		{"Copilot via node", []string{"node", "/usr/lib/node_modules/@github/copilot/index.js"}, TypeCopilot, false},
+		{"Codex binary", []string{"/opt/homebrew/bin/codex"}, TypeCodex, false},
+		{"Codex via node", []string{"node", "/usr/lib/node_modules/@openai/codex/bin/codex.js"}, TypeCodex, false},
+		{"Gemini via node", []string{"node", "--no-warnings", "/usr/lib/node_modules/@google/gemini-cli/dist/index.js"}, TypeGemini, false},
+		{"Aider via python", []string{"/usr/bin/python3", "/home/u/.local/bin/aider", "--model", "sonnet"}, TypeAider, false},
+		{"Aider via python -m", []string{"python", "-m", "aider"}, TypeAider, false},
+		{"OpenCode binary", []string{"opencode"}, TypeOpenCode, false},
+		{"Node app", []string{"node", "server.js"}, "", true},
+		{"Python script", []string{"python3", "manage.py", "runserver"}, "", true},
+		{"Editor opening a file named claude", []string{"vim", "claude"}, "", true},
+		{"Shell", []string{"-zsh"}, "", true},
+		{"Empty", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectArgs(tt.args)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("DetectArgs(%q) = %v, want nil", tt.args, got.Type())
+				}
+				return
+			}
+			if got == nil {
+				t.Errorf("DetectArgs(%q) = nil, want %v", tt.args, tt.wantType)
+				return
+			}
+			if got.Type() != tt.wantType {
+				t.Errorf("DetectArgs(%q).Type() = %v, want %v", tt.args, got.Type(), tt.wantType)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"path/filepath"
 	"regexp"
 	"strings"
 	"unicode"
@@ -51,6 +52,46 @@ func (a *ClaudeAgent) MayBeProcess(currentCommand string) bool {
 	return currentCommand == "node" || currentCommand == "claude" || claudeVersionPattern.MatchString(currentCommand)
 }
 
+// MayBeArgs checks if the command line runs Claude Code.
+// e.g., "claude", "node .../@anthropic-ai/claude-code/cli.js", ".../claude/versions/2.1.34".
+func (a *ClaudeAgent) MayBeArgs(args []string) bool {
+	path := programPath(args)
+	name := filepath.Base(path)
+	return name == "claude" ||
+		strings.Contains(path, "@anthropic-ai/claude-code") ||
+		(claudeVersionPattern.MatchString(name) && strings.Contains(path, "/claude/"))
+}
+
+// claudeSignaturePattern matches Claude Code's on-screen chrome (footer hints, mode indicators, welcome banner).
+// "esc to interrupt" is only matched in Claude Code's forms with middle dots, since Codex CLI shows it too ("(29s • esc to interrupt)").
+var claudeSignaturePattern = regexp.MustCompile(`\? for shortcuts|\(esc to interrupt ·|· esc to interrupt|⏵⏵ accept edits on|⏸ plan mode on|Welcome to Claude Code|Claude Code v\d`)
+
+// Score returns the detection confidence for Claude Code.
+// The title is required unless the command line is resolved, because "node" may be anything.
+func (a *ClaudeAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs,
+		func(c string) bool { return c == "claude" || claudeVersionPattern.MatchString(c) },
+		func(c string) bool { return c == "node" })
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if a.MayBeTitle(s.Title) {
+		score.Title = scoreTitleDistinct
+	} else if process != scoreProcessArgs {
+		return Score{}
+	}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// MayBeContent checks if the pane content shows Claude Code's chrome.
+func (a *ClaudeAgent) MayBeContent(content string) bool {
+	return claudeSignaturePattern.MatchString(content)
+}
+
 // ExtractSummary extracts the task summary from the pane title.
 func (a *ClaudeAgent) ExtractSummary(title string) string {
 	// Remove the "✳ " prefix
@@ -1,6 +1,9 @@
 package agent
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 // CodexAgent detects and parses Codex CLI instances.
 type CodexAgent struct{}
@@ -25,6 +28,38 @@ func (a *CodexAgent) MayBeProcess(currentCommand string) bool {
 	return currentCommand == "codex" || strings.HasPrefix(currentCommand, "codex-")
 }
 
+// MayBeArgs checks if the command line runs Codex CLI.
+// e.g., "codex", "node .../@openai/codex/bin/codex.js".
+func (a *CodexAgent) MayBeArgs(args []string) bool {
+	name := strings.TrimSuffix(programName(args), ".js")
+	return a.MayBeProcess(name) || strings.Contains(programPath(args), "@openai/codex")
+}
+
+// codexSignaturePattern matches Codex CLI's on-screen chrome (banner, context footer).
+// Gemini CLI shows "(97% context left)" in parentheses, so it is excluded.
+var codexSignaturePattern = regexp.MustCompile(`OpenAI Codex|(?:^|\s)\d{1,3}% context left(?:\s|$)|\b\d{1,3}% left · `)
+
+// Score returns the detection confidence for Codex CLI.
+func (a *CodexAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs, a.MayBeProcess, nil)
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if strings.TrimSpace(s.Title) == "Codex" {
+		score.Title = scoreTitleWeak
+	}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// MayBeContent checks if the pane content shows Codex CLI's chrome.
+func (a *CodexAgent) MayBeContent(content string) bool {
+	return codexSignaturePattern.MatchString(content)
+}
+
 // ExtractSummary extracts the task summary from the pane title.
 func (a *CodexAgent) ExtractSummary(title string) string {
 	// Remove common emoji prefixes.
@@ -82,6 +82,7 @@ func TestCodexAgent_ParseStatus(t *testing.T) {
 		content   string
 		wantState string
 		wantMode  string
+		wantDesc  string
 	}{
 		{
 			name: "Running with Esc to cancel",
@@ -93,6 +94,13 @@ func TestCodexAgent_ParseStatus(t *testing.T) {
 			name:      "Running with elapsed and esc to interrupt",
 			content:   `• Working (29s • esc to interrupt)`,
 			wantState: StateRunning,
+			wantDesc:  "29s",
+		},
+		{
+			name:      "Running with minutes elapsed",
+			content:   `• Working (1m 29s • esc to interrupt)`,
+			wantState: StateRunning,
+			wantDesc:  "1m 29s",
 		},
 		{
 			name: "Waiting with command approval",
@@ -155,6 +163,9 @@ without any recognizable pattern`,
 			if got.Mode != tt.wantMode {
 				t.Errorf("CodexAgent.ParseStatus().Mode = %q, want %q", got.Mode, tt.wantMode)
 			}
+			if got.Description != tt.wantDesc {
+				t.Errorf("CodexAgent.ParseStatus().Description = %q, want %q", got.Description, tt.wantDesc)
+			}
 		})
 	}
 }
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Config is the user configuration of agents (agents.yaml).
+type Config struct {
+	Agents []CustomAgentConfig `yaml:"agents"`
+}
+
+// DefaultConfigPath returns the path of agents.yaml ($XDG_CONFIG_HOME/tcmux/agents.yaml).
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "tcmux", "agents.yaml")
+}
+
+// LoadConfig loads user-defined agents from the config file.
+// A missing config file is not an error.
+func LoadConfig(path string) ([]*CustomAgent, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var agents []*CustomAgent
+	for _, ac := range c.Agents {
+		a, err := NewCustomAgent(ac)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		agents = append(agents, a)
+	}
+	return agents, nil
+}
+
+// Register adds detectors to the registry.
+// User-defined detectors win ties in scores with the built-in ones,
+// so they can claim ambiguous processes such as "node".
+func Register(ds ...Detector) {
+	detectors = append(append([]Detector{}, ds...), detectors...)
+}
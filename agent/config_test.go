@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agents.yaml")
+	if err := os.WriteFile(path, []byte(`agents:
+  - name: myagent
+    icon: "◎"
+    color: "#FF8800"
+    process:
+      - ^myagent$
+    running:
+      - esc to stop
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	agents, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agents) != 1 {
+		t.Fatalf("LoadConfig() returned %d agents, want 1", len(agents))
+	}
+	a := agents[0]
+	if a.Type() != "myagent" || a.Icon() != "◎" || a.Color() != "#FF8800" {
+		t.Errorf("LoadConfig() = {%q, %q, %q}, want {%q, %q, %q}", a.Type(), a.Icon(), a.Color(), "myagent", "◎", "#FF8800")
+	}
+	if got := a.ParseStatus("esc to stop").State; got != StateRunning {
+		t.Errorf("ParseStatus().State = %q, want %q", got, StateRunning)
+	}
+}
+
+func TestLoadConfig_NotExist(t *testing.T) {
+	agents, err := LoadConfig(filepath.Join(t.TempDir(), "agents.yaml"))
+	if err != nil {
+		t.Errorf("LoadConfig() error = %v, want nil", err)
+	}
+	if len(agents) != 0 {
+		t.Errorf("LoadConfig() returned %d agents, want 0", len(agents))
+	}
+}
+
+func TestRegister(t *testing.T) {
+	orig := detectors
+	t.Cleanup(func() { detectors = orig })
+
+	a, err := NewCustomAgent(CustomAgentConfig{
+		Name:    "myagent",
+		Process: []string{`^node$`},
+		Title:   []string{`^MyAgent`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Register(a)
+
+	if got := Detect("MyAgent", "node"); got == nil || got.Type() != "myagent" {
+		t.Errorf("Detect() after Register() = %v, want myagent", got)
+	}
+	if got := Detect("✳ Task summary", "node"); got == nil || got.Type() != TypeClaude {
+		t.Errorf("Detect() after Register() = %v, want %v", got, TypeClaude)
+	}
+}
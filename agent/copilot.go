@@ -1,6 +1,9 @@
 package agent
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 // CopilotAgent detects and parses GitHub Copilot CLI instances.
 type CopilotAgent struct{}
@@ -28,6 +31,36 @@ func (a *CopilotAgent) MayBeProcess(currentCommand string) bool {
 	return currentCommand == "copilot"
 }
 
+// MayBeArgs checks if the command line runs Copilot CLI.
+// e.g., "copilot", "node .../@github/copilot/index.js".
+func (a *CopilotAgent) MayBeArgs(args []string) bool {
+	return programName(args) == "copilot" || strings.Contains(programPath(args), "@github/copilot")
+}
+
+// copilotSignaturePattern matches Copilot CLI's on-screen chrome (prompt placeholder, footer).
+var copilotSignaturePattern = regexp.MustCompile(`Type @ to mention files|Remaining requests:|Welcome to GitHub Copilot CLI`)
+
+// Score returns the detection confidence for Copilot CLI.
+func (a *CopilotAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs, a.MayBeProcess, nil)
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if strings.TrimSpace(s.Title) == "GitHub Copilot" {
+		score.Title = scoreTitleWeak
+	}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// MayBeContent checks if the pane content shows Copilot CLI's chrome.
+func (a *CopilotAgent) MayBeContent(content string) bool {
+	return copilotSignaturePattern.MatchString(content)
+}
+
 // ExtractSummary extracts the task summary from the pane title.
 func (a *CopilotAgent) ExtractSummary(title string) string {
 	// Remove common emoji prefixes
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CustomAgentConfig is the declarative definition of a user-defined agent.
+type CustomAgentConfig struct {
+	Name     string   `yaml:"name"`
+	Icon     string   `yaml:"icon"`
+	Color    string   `yaml:"color"`     // Theme color (e.g., "#FF8800")
+	Process  []string `yaml:"process"`   // Regular expressions for pane_current_command
+	Title    []string `yaml:"title"`     // Regular expressions for pane_title (empty means any title)
+	Running  []string `yaml:"running"`   // Regular expressions for Running state (first submatch is used as description)
+	Waiting  []string `yaml:"waiting"`   // Regular expressions for Waiting state
+	Idle     []string `yaml:"idle"`      // Regular expressions for Idle state
+	PlanMode []string `yaml:"plan_mode"` // Regular expressions for plan mode
+}
+
+// CustomAgent detects and parses a user-defined agent.
+type CustomAgent struct {
+	name     Type
+	icon     string
+	color    string
+	process  []*regexp.Regexp
+	title    []*regexp.Regexp
+	running  []*regexp.Regexp
+	waiting  []*regexp.Regexp
+	idle     []*regexp.Regexp
+	planMode []*regexp.Regexp
+}
+
+// NewCustomAgent creates a CustomAgent from its config.
+func NewCustomAgent(c CustomAgentConfig) (*CustomAgent, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("agent name is required")
+	}
+	if len(c.Process) == 0 {
+		return nil, fmt.Errorf("agent %q: at least one process matcher is required", c.Name)
+	}
+	a := &CustomAgent{
+		name:  Type(c.Name),
+		icon:  c.Icon,
+		color: c.Color,
+	}
+	if a.icon == "" {
+		a.icon = "●"
+	}
+	var err error
+	for _, f := range []struct {
+		key      string
+		patterns []string
+		dest     *[]*regexp.Regexp
+	}{
+		{"process", c.Process, &a.process},
+		{"title", c.Title, &a.title},
+		{"running", c.Running, &a.running},
+		{"waiting", c.Waiting, &a.waiting},
+		{"idle", c.Idle, &a.idle},
+		{"plan_mode", c.PlanMode, &a.planMode},
+	} {
+		*f.dest, err = compilePatterns(f.patterns)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: invalid %s pattern: %w", c.Name, f.key, err)
+		}
+	}
+	return a, nil
+}
+
+func (a *CustomAgent) Type() Type {
+	return a.name
+}
+
+func (a *CustomAgent) Icon() string {
+	return a.icon
+}
+
+// Color returns the theme color of the agent (empty means default).
+func (a *CustomAgent) Color() string {
+	return a.color
+}
+
+// MayBeTitle checks if the pane title matches any title matcher.
+// Without title matchers, any title is accepted.
+func (a *CustomAgent) MayBeTitle(title string) bool {
+	if len(a.title) == 0 {
+		return true
+	}
+	return matchAny(a.title, title)
+}
+
+// MayBeProcess checks if the current command matches any process matcher.
+func (a *CustomAgent) MayBeProcess(currentCommand string) bool {
+	return matchAny(a.process, currentCommand)
+}
+
+// Score returns the detection confidence for the user-defined agent.
+// Process matchers are matched against pane_current_command only.
+// Status patterns matching the pane content count as its content signature.
+func (a *CustomAgent) Score(s Signals) Score {
+	if !a.MayBeTitle(s.Title) || !a.MayBeProcess(s.CurrentCommand) {
+		return Score{}
+	}
+	score := Score{Process: scoreProcessExact}
+	if len(a.title) > 0 {
+		score.Title = scoreTitleDistinct
+	}
+	if s.Content != "" && (matchAny(a.running, s.Content) || matchAny(a.waiting, s.Content) || matchAny(a.idle, s.Content)) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// ExtractSummary extracts the task summary from the pane title.
+// If a title matcher has a submatch, the first submatch is used as the summary.
+func (a *CustomAgent) ExtractSummary(title string) string {
+	for _, re := range a.title {
+		if matches := re.FindStringSubmatch(title); len(matches) > 1 {
+			return strings.TrimSpace(matches[1])
+		}
+	}
+	return ""
+}
+
+// ParseStatus parses the pane content with the configured patterns.
+// States are checked in the order Running, Waiting, Idle.
+func (a *CustomAgent) ParseStatus(content string) Status {
+	lines := strings.Split(content, "\n")
+	lastLines := lastNonEmptyLines(lines, 30)
+	combined := strings.Join(lastLines, "\n")
+
+	status := Status{
+		State:       StateUnknown,
+		Mode:        "",
+		Description: "",
+	}
+
+	if matchAny(a.planMode, combined) {
+		status.Mode = ModePlan
+	}
+
+	for _, re := range a.running {
+		if matches := re.FindStringSubmatch(combined); len(matches) > 0 {
+			status.State = StateRunning
+			if len(matches) > 1 {
+				status.Description = strings.TrimSpace(matches[1])
+			}
+			return status
+		}
+	}
+
+	if matchAny(a.waiting, combined) {
+		status.State = StateWaiting
+		return status
+	}
+
+	if matchAny(a.idle, combined) {
+		status.State = StateIdle
+		return status
+	}
+
+	return status
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package agent
+
+import "testing"
+
+func TestNewCustomAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CustomAgentConfig
+		wantErr bool
+	}{
+		{
+			name:   "Valid config",
+			config: CustomAgentConfig{Name: "myagent", Process: []string{`^myagent$`}},
+		},
+		{
+			name:    "Missing name",
+			config:  CustomAgentConfig{Process: []string{`^myagent$`}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing process matcher",
+			config:  CustomAgentConfig{Name: "myagent"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid pattern",
+			config:  CustomAgentConfig{Name: "myagent", Process: []string{`^myagent$`}, Running: []string{`(`}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCustomAgent(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCustomAgent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomAgent_Detect(t *testing.T) {
+	a, err := NewCustomAgent(CustomAgentConfig{
+		Name:    "myagent",
+		Icon:    "◎",
+		Process: []string{`^node$`, `^myagent$`},
+		Title:   []string{`^MyAgent: (.*)$`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		title          string
+		currentCommand string
+		want           bool
+		wantSummary    string
+	}{
+		{"Title and process match", "MyAgent: Fix bug", "myagent", true, "Fix bug"},
+		{"Node with matching title", "MyAgent: Fix bug", "node", true, "Fix bug"},
+		{"Title does not match", "zsh", "myagent", false, ""},
+		{"Process does not match", "MyAgent: Fix bug", "zsh", false, "Fix bug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.MayBeTitle(tt.title) && a.MayBeProcess(tt.currentCommand)
+			if got != tt.want {
+				t.Errorf("CustomAgent detection(%q, %q) = %v, want %v", tt.title, tt.currentCommand, got, tt.want)
+			}
+			if summary := a.ExtractSummary(tt.title); summary != tt.wantSummary {
+				t.Errorf("CustomAgent.ExtractSummary(%q) = %q, want %q", tt.title, summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestCustomAgent_ParseStatus(t *testing.T) {
+	a, err := NewCustomAgent(CustomAgentConfig{
+		Name:     "myagent",
+		Process:  []string{`^myagent$`},
+		Running:  []string{`Working \((\d+s)\)`, `(?m)^Thinking`},
+		Waiting:  []string{`Approve\? \[y/n\]`},
+		Idle:     []string{`(?m)^\$ $`},
+		PlanMode: []string{`\[plan\]`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		wantState string
+		wantMode  string
+		wantDesc  string
+	}{
+		{
+			name:      "Running with description",
+			content:   "output\nWorking (12s)",
+			wantState: StateRunning,
+			wantDesc:  "12s",
+		},
+		{
+			name:      "Running without description",
+			content:   "output\nThinking...",
+			wantState: StateRunning,
+		},
+		{
+			name:      "Waiting",
+			content:   "rm -rf build\nApprove? [y/n]",
+			wantState: StateWaiting,
+		},
+		{
+			name:      "Idle in plan mode",
+			content:   "[plan]\n$ ",
+			wantState: StateIdle,
+			wantMode:  ModePlan,
+		},
+		{
+			name:      "Unknown state",
+			content:   "Some random output",
+			wantState: StateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.ParseStatus(tt.content)
+			if got.State != tt.wantState {
+				t.Errorf("CustomAgent.ParseStatus().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Mode != tt.wantMode {
+				t.Errorf("CustomAgent.ParseStatus().Mode = %q, want %q", got.Mode, tt.wantMode)
+			}
+			if got.Description != tt.wantDesc {
+				t.Errorf("CustomAgent.ParseStatus().Description = %q, want %q", got.Description, tt.wantDesc)
+			}
+		})
+	}
+}
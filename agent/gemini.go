@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Gemini CLI title prefixes (shown when dynamic window titles are enabled)
+const (
+	geminiPrefixReady   = "◇" // Ready
+	geminiPrefixWorking = "✦" // Working
+	geminiPrefixAction  = "✋" // Action required
+)
+
+// GeminiAgent detects and parses Gemini CLI instances.
+type GeminiAgent struct{}
+
+func (a *GeminiAgent) Type() Type {
+	return TypeGemini
+}
+
+func (a *GeminiAgent) Icon() string {
+	return "✦"
+}
+
+// MayBeTitle checks if the pane title may indicate a Gemini CLI instance.
+// Gemini CLI sets the title to "Gemini - <dir>", or to a status prefix
+// followed by the task when dynamic window titles are enabled.
+// The title must be checked because Gemini CLI may run as "node".
+func (a *GeminiAgent) MayBeTitle(title string) bool {
+	title = strings.TrimSpace(title)
+	return title == "Gemini" ||
+		strings.HasPrefix(title, "Gemini - ") ||
+		strings.HasPrefix(title, geminiPrefixReady) ||
+		strings.HasPrefix(title, geminiPrefixWorking) ||
+		strings.HasPrefix(title, geminiPrefixAction)
+}
+
+// MayBeProcess checks if the current command may be a Gemini CLI process.
+// Gemini CLI runs as "gemini" or "node" (npm install).
+func (a *GeminiAgent) MayBeProcess(currentCommand string) bool {
+	return currentCommand == "gemini" || currentCommand == "node"
+}
+
+// MayBeArgs checks if the command line runs Gemini CLI.
+// e.g., "gemini", "node .../@google/gemini-cli/dist/index.js".
+func (a *GeminiAgent) MayBeArgs(args []string) bool {
+	return programName(args) == "gemini" || strings.Contains(programPath(args), "@google/gemini-cli")
+}
+
+// geminiSignaturePattern matches Gemini CLI's on-screen chrome (input placeholder, footer).
+var geminiSignaturePattern = regexp.MustCompile(`Type your message or @path/to/file|\(\d{1,3}% context left\)|no sandbox \(see /docs\)`)
+
+// Score returns the detection confidence for Gemini CLI.
+// The title is required unless the command line is resolved, because "node" may be anything.
+func (a *GeminiAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs,
+		func(c string) bool { return c == "gemini" },
+		func(c string) bool { return c == "node" })
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if a.MayBeTitle(s.Title) {
+		score.Title = scoreTitleDistinct
+	} else if process != scoreProcessArgs {
+		return Score{}
+	}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// MayBeContent checks if the pane content shows Gemini CLI's chrome.
+func (a *GeminiAgent) MayBeContent(content string) bool {
+	return geminiSignaturePattern.MatchString(content)
+}
+
+// ExtractSummary extracts the task summary from the pane title.
+func (a *GeminiAgent) ExtractSummary(title string) string {
+	title = strings.TrimSpace(title)
+	// "Gemini - <dir>" is the default title, return empty
+	if title == "Gemini" || strings.HasPrefix(title, "Gemini - ") {
+		return ""
+	}
+	for _, prefix := range []string{geminiPrefixReady, geminiPrefixWorking, geminiPrefixAction} {
+		if strings.HasPrefix(title, prefix) {
+			title = strings.TrimSpace(strings.TrimPrefix(title, prefix))
+			break
+		}
+	}
+	// Remove the trailing directory: "Working… (tcmux)"
+	if i := strings.LastIndex(title, " ("); i >= 0 && strings.HasSuffix(title, ")") {
+		title = strings.TrimSpace(title[:i])
+	}
+	// Generic state labels are not a summary
+	switch title {
+	case "Ready", "Working…", "Action Required":
+		return ""
+	}
+	return title
+}
+
+// ParseStatus parses the pane content and determines the Gemini CLI status.
+func (a *GeminiAgent) ParseStatus(content string) Status {
+	return parseGeminiStatus(content)
+}
@@ -0,0 +1,181 @@
+package agent
+
+import "testing"
+
+func TestGeminiAgent_MayBeTitle(t *testing.T) {
+	agent := &GeminiAgent{}
+	tests := []struct {
+		name  string
+		title string
+		want  bool
+	}{
+		{"Gemini default title", "Gemini - tcmux", true},
+		{"Ready title", "◇  Ready (tcmux)", true},
+		{"Working title", "✦  Refactoring parser (tcmux)", true},
+		{"Action required title", "✋  Action Required (tcmux)", true},
+		{"Claude Code title", "✳ Task summary", false},
+		{"Normal shell", "zsh", false},
+		{"Empty title", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.MayBeTitle(tt.title)
+			if got != tt.want {
+				t.Errorf("GeminiAgent.MayBeTitle(%q) = %v, want %v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiAgent_MayBeProcess(t *testing.T) {
+	agent := &GeminiAgent{}
+	tests := []struct {
+		name           string
+		currentCommand string
+		want           bool
+	}{
+		{"Gemini binary", "gemini", true},
+		{"Node process", "node", true},
+		{"Claude binary", "claude", false},
+		{"Zsh shell", "zsh", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.MayBeProcess(tt.currentCommand)
+			if got != tt.want {
+				t.Errorf("GeminiAgent.MayBeProcess(%q) = %v, want %v", tt.currentCommand, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiAgent_ExtractSummary(t *testing.T) {
+	agent := &GeminiAgent{}
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"Default title", "Gemini - tcmux", ""},
+		{"Ready title", "◇  Ready (tcmux)", ""},
+		{"Action required title", "✋  Action Required (tcmux)", ""},
+		{"Working title with thought", "✦  Refactoring parser (tcmux)", "Refactoring parser"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ExtractSummary(tt.title)
+			if got != tt.want {
+				t.Errorf("GeminiAgent.ExtractSummary(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiAgent_ParseStatus(t *testing.T) {
+	agent := &GeminiAgent{}
+	tests := []struct {
+		name      string
+		content   string
+		wantState string
+		wantMode  string
+		wantDesc  string
+	}{
+		{
+			name: "Running with elapsed time",
+			content: `✦ I'll start by reading the parser.
+
+⠏ Analyzing the parser structure (esc to cancel, 1m 5s)
+
+╭────────────────────────────────────────────────────────────────╮
+│ >   Type your message or @path/to/file                         │
+╰────────────────────────────────────────────────────────────────╯
+~/src/github.com/k1LoW/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantState: StateRunning,
+			wantDesc:  "1m 5s",
+		},
+		{
+			name:      "Running without elapsed time",
+			content:   `⠋ Thinking (esc to cancel)`,
+			wantState: StateRunning,
+		},
+		{
+			name: "Idle with input box",
+			content: `✦ Done. The parser now handles nested conditionals.
+
+╭────────────────────────────────────────────────────────────────╮
+│ >   Type your message or @path/to/file                         │
+╰────────────────────────────────────────────────────────────────╯
+~/src/github.com/k1LoW/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantState: StateIdle,
+		},
+		{
+			name: "Idle with multi-line input",
+			content: `╭────────────────────────────────────────────────────────────────╮
+│ > first line                                                   │
+│   second line                                                  │
+╰────────────────────────────────────────────────────────────────╯
+~/src/github.com/k1LoW/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantState: StateIdle,
+		},
+		{
+			name: "Idle with accepting edits",
+			content: `accepting edits (shift + tab to toggle)
+╭────────────────────────────────────────────────────────────────╮
+│ >   Type your message or @path/to/file                         │
+╰────────────────────────────────────────────────────────────────╯
+~/src/github.com/k1LoW/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantState: StateIdle,
+			wantMode:  ModeAcceptEdits,
+		},
+		{
+			name: "Waiting with shell confirmation",
+			content: `╭────────────────────────────────────────────────────────────────╮
+│ ?  Shell go test ./...                                         │
+│                                                                │
+│ Allow execution of: 'go'?                                      │
+│                                                                │
+│ ● 1. Yes, allow once                                           │
+│   2. Yes, allow always ...                                     │
+│   3. No, suggest changes (esc)                                 │
+╰────────────────────────────────────────────────────────────────╯
+
+⠏ Waiting for user confirmation...
+~/src/github.com/k1LoW/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantState: StateWaiting,
+		},
+		{
+			name: "Waiting with edit confirmation",
+			content: `│ Apply this change?                                             │
+│                                                                │
+│ ● 1. Yes, allow once                                           │
+│   2. Modify with external editor                               │
+╰────────────────────────────────────────────────────────────────╯`,
+			wantState: StateWaiting,
+		},
+		{
+			name: "Unknown state",
+			content: `Some random output
+without any recognizable pattern`,
+			wantState: StateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ParseStatus(tt.content)
+			if got.State != tt.wantState {
+				t.Errorf("GeminiAgent.ParseStatus().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Mode != tt.wantMode {
+				t.Errorf("GeminiAgent.ParseStatus().Mode = %q, want %q", got.Mode, tt.wantMode)
+			}
+			if got.Description != tt.wantDesc {
+				t.Errorf("GeminiAgent.ParseStatus().Description = %q, want %q", got.Description, tt.wantDesc)
+			}
+		})
+	}
+}
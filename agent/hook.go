@@ -0,0 +1,22 @@
+package agent
+
+import "time"
+
+// HookRecord is the state of an agent instance reported by the agent itself
+// (e.g., Claude Code hooks), keyed by the tmux pane the agent runs in.
+type HookRecord struct {
+	Agent     Type      `json:"agent"`
+	PaneID    string    `json:"pane_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	Event     string    `json:"event"`
+	State     string    `json:"state"`
+	Mode      string    `json:"mode,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HookResolver is implemented by detectors whose status can be reported by hooks.
+// ResolveStatus reconciles the status parsed from the screen with the hook record.
+type HookResolver interface {
+	ResolveStatus(screen Status, rec *HookRecord, now time.Time) Status
+}
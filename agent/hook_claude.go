@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// claudeHookTTL is how long a hook record is trusted without updates.
+	// A Waiting or Idle agent may stay untouched for hours, so it is generous.
+	claudeHookTTL = 24 * time.Hour
+
+	// claudeHookGrace is how long a hook record overrides an Idle screen.
+	// Claude Code does not run the Stop hook when the user interrupts it with Esc,
+	// so a Running record contradicted by an Idle prompt is stale after this period.
+	claudeHookGrace = 3 * time.Second
+)
+
+// Claude Code hook events
+const (
+	ClaudeHookSessionStart     = "SessionStart"
+	ClaudeHookSessionEnd       = "SessionEnd"
+	ClaudeHookUserPromptSubmit = "UserPromptSubmit"
+	ClaudeHookPreToolUse       = "PreToolUse"
+	ClaudeHookPostToolUse      = "PostToolUse"
+	ClaudeHookNotification     = "Notification"
+	ClaudeHookStop             = "Stop"
+	ClaudeHookSubagentStop     = "SubagentStop"
+	ClaudeHookPreCompact       = "PreCompact"
+)
+
+// claudeHookInput is the JSON payload Claude Code passes to hooks on stdin.
+type claudeHookInput struct {
+	SessionID        string `json:"session_id"`
+	HookEventName    string `json:"hook_event_name"`
+	PermissionMode   string `json:"permission_mode"`
+	Message          string `json:"message"`
+	NotificationType string `json:"notification_type"`
+}
+
+// ParseHook parses the JSON payload of a Claude Code hook into a hook record.
+func (a *ClaudeAgent) ParseHook(payload []byte, paneID string, now time.Time) (*HookRecord, error) {
+	var in claudeHookInput
+	if err := json.Unmarshal(payload, &in); err != nil {
+		return nil, fmt.Errorf("invalid hook payload: %w", err)
+	}
+
+	rec := &HookRecord{
+		Agent:     TypeClaude,
+		PaneID:    paneID,
+		SessionID: in.SessionID,
+		Event:     in.HookEventName,
+		Mode:      claudePermissionMode(in.PermissionMode),
+		Message:   in.Message,
+		UpdatedAt: now,
+	}
+
+	switch in.HookEventName {
+	case ClaudeHookSessionStart, ClaudeHookStop, ClaudeHookSessionEnd:
+		rec.State = StateIdle
+	case ClaudeHookUserPromptSubmit, ClaudeHookPreToolUse, ClaudeHookPostToolUse, ClaudeHookSubagentStop, ClaudeHookPreCompact:
+		rec.State = StateRunning
+	case ClaudeHookNotification:
+		// "Claude is waiting for your input" is sent after the prompt has been idle for a while
+		if in.NotificationType == "idle_prompt" || in.Message == "Claude is waiting for your input" {
+			rec.State = StateIdle
+		} else {
+			rec.State = StateWaiting
+		}
+	default:
+		return nil, fmt.Errorf("unsupported hook event: %q", in.HookEventName)
+	}
+
+	return rec, nil
+}
+
+// ResolveStatus prefers a fresh hook record over the status parsed from the screen.
+// The screen still provides the elapsed time while running, and the mode
+// when the hook payload does not include the permission mode.
+func (a *ClaudeAgent) ResolveStatus(screen Status, rec *HookRecord, now time.Time) Status {
+	if rec == nil || rec.State == "" || now.Sub(rec.UpdatedAt) > claudeHookTTL {
+		return screen
+	}
+	if rec.State != StateIdle && screen.State == StateIdle && now.Sub(rec.UpdatedAt) > claudeHookGrace {
+		return screen
+	}
+
+	status := screen
+	status.State = rec.State
+	if rec.Mode != "" {
+		status.Mode = rec.Mode
+	}
+	if status.State != StateRunning {
+		status.Description = ""
+	}
+	return status
+}
+
+// claudePermissionMode maps the permission_mode of a hook payload to a mode.
+func claudePermissionMode(mode string) string {
+	switch mode {
+	case "plan":
+		return ModePlan
+	case "acceptEdits":
+		return ModeAcceptEdits
+	default:
+		return ""
+	}
+}
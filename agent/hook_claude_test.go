@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaudeAgent_ParseHook(t *testing.T) {
+	agent := &ClaudeAgent{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		payload   string
+		wantState string
+		wantMode  string
+		wantErr   bool
+	}{
+		{
+			name:      "UserPromptSubmit",
+			payload:   `{"session_id":"abc","hook_event_name":"UserPromptSubmit","prompt":"Fix the bug","permission_mode":"default"}`,
+			wantState: StateRunning,
+		},
+		{
+			name:      "PreToolUse in plan mode",
+			payload:   `{"session_id":"abc","hook_event_name":"PreToolUse","tool_name":"Read","permission_mode":"plan"}`,
+			wantState: StateRunning,
+			wantMode:  ModePlan,
+		},
+		{
+			name:      "Notification for permission",
+			payload:   `{"session_id":"abc","hook_event_name":"Notification","message":"Claude needs your permission to use Bash","notification_type":"permission_prompt"}`,
+			wantState: StateWaiting,
+		},
+		{
+			name:      "Notification for idle prompt",
+			payload:   `{"session_id":"abc","hook_event_name":"Notification","message":"Claude is waiting for your input"}`,
+			wantState: StateIdle,
+		},
+		{
+			name:      "Stop",
+			payload:   `{"session_id":"abc","hook_event_name":"Stop","stop_hook_active":false}`,
+			wantState: StateIdle,
+		},
+		{
+			name:      "SessionStart",
+			payload:   `{"session_id":"abc","hook_event_name":"SessionStart","source":"startup"}`,
+			wantState: StateIdle,
+		},
+		{
+			name:    "Unsupported event",
+			payload: `{"session_id":"abc","hook_event_name":"Unknown"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			payload: `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := agent.ParseHook([]byte(tt.payload), "%1", now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ClaudeAgent.ParseHook() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.State != tt.wantState {
+				t.Errorf("ClaudeAgent.ParseHook().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Mode != tt.wantMode {
+				t.Errorf("ClaudeAgent.ParseHook().Mode = %q, want %q", got.Mode, tt.wantMode)
+			}
+			if got.PaneID != "%1" || got.SessionID != "abc" || !got.UpdatedAt.Equal(now) {
+				t.Errorf("ClaudeAgent.ParseHook() = %+v, want pane %q, session %q, updated at %v", got, "%1", "abc", now)
+			}
+		})
+	}
+}
+
+func TestClaudeAgent_ResolveStatus(t *testing.T) {
+	agent := &ClaudeAgent{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		screen Status
+		rec    *HookRecord
+		want   Status
+	}{
+		{
+			name:   "No record",
+			screen: Status{State: StateIdle},
+			rec:    nil,
+			want:   Status{State: StateIdle},
+		},
+		{
+			name:   "Waiting record overrides unrecognised screen",
+			screen: Status{State: StateUnknown},
+			rec:    &HookRecord{State: StateWaiting, Event: ClaudeHookNotification, UpdatedAt: now.Add(-10 * time.Minute)},
+			want:   Status{State: StateWaiting},
+		},
+		{
+			name:   "Stop record overrides running screen",
+			screen: Status{State: StateRunning, Description: "1m 5s"},
+			rec:    &HookRecord{State: StateIdle, Event: ClaudeHookStop, UpdatedAt: now.Add(-time.Second)},
+			want:   Status{State: StateIdle},
+		},
+		{
+			name:   "Running record keeps elapsed time from screen",
+			screen: Status{State: StateRunning, Description: "30s"},
+			rec:    &HookRecord{State: StateRunning, Event: ClaudeHookPreToolUse, Mode: ModePlan, UpdatedAt: now.Add(-time.Second)},
+			want:   Status{State: StateRunning, Description: "30s", Mode: ModePlan},
+		},
+		{
+			name:   "Running record just submitted overrides idle screen",
+			screen: Status{State: StateIdle},
+			rec:    &HookRecord{State: StateRunning, Event: ClaudeHookUserPromptSubmit, UpdatedAt: now.Add(-time.Second)},
+			want:   Status{State: StateRunning},
+		},
+		{
+			name:   "Running record after interrupt is stale on idle screen",
+			screen: Status{State: StateIdle},
+			rec:    &HookRecord{State: StateRunning, Event: ClaudeHookPreToolUse, UpdatedAt: now.Add(-time.Minute)},
+			want:   Status{State: StateIdle},
+		},
+		{
+			name:   "Expired record",
+			screen: Status{State: StateRunning},
+			rec:    &HookRecord{State: StateIdle, Event: ClaudeHookStop, UpdatedAt: now.Add(-48 * time.Hour)},
+			want:   Status{State: StateRunning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ResolveStatus(tt.screen, tt.rec, now)
+			if got != tt.want {
+				t.Errorf("ClaudeAgent.ResolveStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
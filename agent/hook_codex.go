@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// codexHookTTL is how long a notify record is trusted without updates.
+	codexHookTTL = 24 * time.Hour
+
+	// codexHookGrace is how long a Waiting record overrides an Idle screen.
+	// Codex does not notify when an approval is declined, so a Waiting record
+	// contradicted by an Idle prompt is stale after this period.
+	codexHookGrace = 3 * time.Second
+)
+
+// Codex CLI notify event types
+const (
+	CodexNotifyAgentTurnComplete = "agent-turn-complete"
+	CodexNotifyApprovalRequested = "approval-requested"
+)
+
+// codexNotifyInput is the JSON payload Codex CLI passes to the notify program.
+type codexNotifyInput struct {
+	Type                 string `json:"type"`
+	ThreadID             string `json:"thread-id"`
+	LastAssistantMessage string `json:"last-assistant-message"`
+	Message              string `json:"message"`
+}
+
+// ParseHook parses the JSON payload of a Codex CLI notify event into a hook record.
+func (a *CodexAgent) ParseHook(payload []byte, paneID string, now time.Time) (*HookRecord, error) {
+	var in codexNotifyInput
+	if err := json.Unmarshal(payload, &in); err != nil {
+		return nil, fmt.Errorf("invalid notify payload: %w", err)
+	}
+
+	rec := &HookRecord{
+		Agent:     TypeCodex,
+		PaneID:    paneID,
+		SessionID: in.ThreadID,
+		Event:     in.Type,
+		UpdatedAt: now,
+	}
+
+	switch {
+	case in.Type == CodexNotifyAgentTurnComplete:
+		rec.State = StateIdle
+		rec.Message = in.LastAssistantMessage
+	case strings.Contains(in.Type, "approval"):
+		// e.g., "approval-requested", "exec-approval-request", "apply-patch-approval-request"
+		rec.State = StateWaiting
+		rec.Message = in.Message
+	default:
+		return nil, fmt.Errorf("unsupported notify event: %q", in.Type)
+	}
+
+	return rec, nil
+}
+
+// ResolveStatus reconciles the status parsed from the screen with the notify record.
+// Codex does not notify when a turn starts, so a Running screen always wins, and so does
+// a Waiting screen (e.g., an approval prompt in the turn after a finished one). Otherwise
+// the record decides the state, and a finished turn is described with its age
+// (e.g., "finished 3m ago") and the last assistant message.
+func (a *CodexAgent) ResolveStatus(screen Status, rec *HookRecord, now time.Time) Status {
+	if rec == nil || rec.State == "" || now.Sub(rec.UpdatedAt) > codexHookTTL {
+		return screen
+	}
+	if screen.State == StateRunning || (screen.State == StateWaiting && rec.State != StateWaiting) {
+		return screen
+	}
+	if rec.State == StateWaiting && screen.State == StateIdle && now.Sub(rec.UpdatedAt) > codexHookGrace {
+		return screen
+	}
+
+	status := screen
+	status.State = rec.State
+	status.Message = firstLine(rec.Message)
+	if rec.State == StateIdle {
+		status.Description = "finished " + FormatDuration(now.Sub(rec.UpdatedAt)) + " ago"
+	}
+	return status
+}
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCodexAgent_ParseHook(t *testing.T) {
+	agent := &CodexAgent{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		payload     string
+		wantState   string
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:        "Agent turn complete",
+			payload:     `{"type":"agent-turn-complete","thread-id":"t1","turn-id":"12","cwd":"/src","input-messages":["Fix the bug"],"last-assistant-message":"Fixed the bug."}`,
+			wantState:   StateIdle,
+			wantMessage: "Fixed the bug.",
+		},
+		{
+			name:      "Approval requested",
+			payload:   `{"type":"approval-requested","thread-id":"t1"}`,
+			wantState: StateWaiting,
+		},
+		{
+			name:    "Unsupported event",
+			payload: `{"type":"unknown"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			payload: `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := agent.ParseHook([]byte(tt.payload), "%2", now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CodexAgent.ParseHook() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.State != tt.wantState {
+				t.Errorf("CodexAgent.ParseHook().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("CodexAgent.ParseHook().Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if got.Agent != TypeCodex || got.PaneID != "%2" || got.SessionID != "t1" {
+				t.Errorf("CodexAgent.ParseHook() = %+v, want codex record for pane %q, thread %q", got, "%2", "t1")
+			}
+		})
+	}
+}
+
+func TestCodexAgent_ResolveStatus(t *testing.T) {
+	agent := &CodexAgent{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		screen Status
+		rec    *HookRecord
+		want   Status
+	}{
+		{
+			name:   "No record",
+			screen: Status{State: StateIdle},
+			want:   Status{State: StateIdle},
+		},
+		{
+			name:   "Turn complete describes age and last message",
+			screen: Status{State: StateIdle},
+			rec:    &HookRecord{State: StateIdle, Event: CodexNotifyAgentTurnComplete, Message: "Fixed the bug.\n\nDetails...", UpdatedAt: now.Add(-3 * time.Minute)},
+			want:   Status{State: StateIdle, Description: "finished 3m ago", Message: "Fixed the bug."},
+		},
+		{
+			name:   "Turn complete resolves unrecognised prompt",
+			screen: Status{State: StateUnknown, Mode: ModePlan},
+			rec:    &HookRecord{State: StateIdle, Event: CodexNotifyAgentTurnComplete, UpdatedAt: now.Add(-90 * time.Minute)},
+			want:   Status{State: StateIdle, Mode: ModePlan, Description: "finished 1h 30m ago"},
+		},
+		{
+			name:   "Running screen wins over finished turn",
+			screen: Status{State: StateRunning, Description: "12s"},
+			rec:    &HookRecord{State: StateIdle, Event: CodexNotifyAgentTurnComplete, UpdatedAt: now.Add(-time.Minute)},
+			want:   Status{State: StateRunning, Description: "12s"},
+		},
+		{
+			name:   "Waiting screen wins over finished turn",
+			screen: Status{State: StateWaiting},
+			rec:    &HookRecord{State: StateIdle, Event: CodexNotifyAgentTurnComplete, Message: "Fixed the bug.", UpdatedAt: now.Add(-time.Minute)},
+			want:   Status{State: StateWaiting},
+		},
+		{
+			name:   "Approval requested",
+			screen: Status{State: StateUnknown},
+			rec:    &HookRecord{State: StateWaiting, Event: CodexNotifyApprovalRequested, UpdatedAt: now.Add(-time.Minute)},
+			want:   Status{State: StateWaiting},
+		},
+		{
+			name:   "Declined approval is stale on idle screen",
+			screen: Status{State: StateIdle},
+			rec:    &HookRecord{State: StateWaiting, Event: CodexNotifyApprovalRequested, UpdatedAt: now.Add(-time.Minute)},
+			want:   Status{State: StateIdle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ResolveStatus(tt.screen, tt.rec, now)
+			if got != tt.want {
+				t.Errorf("CodexAgent.ResolveStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
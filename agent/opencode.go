@@ -0,0 +1,79 @@
+package agent
+
+import "strings"
+
+// OpenCode title prefix followed by the session title
+const opencodeTitlePrefix = "OC |"
+
+// OpenCodeAgent detects and parses OpenCode instances.
+type OpenCodeAgent struct{}
+
+func (a *OpenCodeAgent) Type() Type {
+	return TypeOpenCode
+}
+
+func (a *OpenCodeAgent) Icon() string {
+	return "▣"
+}
+
+// MayBeTitle checks if the pane title may indicate an OpenCode instance.
+// Title check is permissive - process name is the primary signal.
+func (a *OpenCodeAgent) MayBeTitle(title string) bool {
+	return true
+}
+
+// MayBeProcess checks if the current command may be an OpenCode process.
+func (a *OpenCodeAgent) MayBeProcess(currentCommand string) bool {
+	currentCommand = strings.ToLower(strings.TrimSpace(currentCommand))
+	return currentCommand == "opencode" || strings.HasPrefix(currentCommand, "opencode-")
+}
+
+// MayBeArgs checks if the command line runs OpenCode.
+// e.g., "opencode", "node .../opencode-ai/bin/opencode".
+func (a *OpenCodeAgent) MayBeArgs(args []string) bool {
+	return a.MayBeProcess(programName(args)) || strings.Contains(programPath(args), "opencode-ai")
+}
+
+// Score returns the detection confidence for OpenCode.
+func (a *OpenCodeAgent) Score(s Signals) Score {
+	process := processScore(s, a.MayBeArgs, a.MayBeProcess, nil)
+	if process == 0 {
+		return Score{}
+	}
+	score := Score{Process: process}
+	if title := strings.TrimSpace(s.Title); strings.HasPrefix(title, opencodeTitlePrefix) || strings.EqualFold(title, "opencode") {
+		score.Title = scoreTitleDistinct
+	}
+	if a.MayBeContent(s.Content) {
+		score.Content = scoreContent
+	}
+	return score
+}
+
+// MayBeContent checks if the pane content shows OpenCode's chrome (input box hints).
+func (a *OpenCodeAgent) MayBeContent(content string) bool {
+	for _, pattern := range opencodeIdlePatterns {
+		if strings.Contains(content, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractSummary extracts the task summary from the pane title.
+func (a *OpenCodeAgent) ExtractSummary(title string) string {
+	title = strings.TrimSpace(title)
+	if strings.HasPrefix(title, opencodeTitlePrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(title, opencodeTitlePrefix))
+	}
+	// "opencode" / "OpenCode" is the default title, return empty
+	if strings.EqualFold(title, "opencode") {
+		return ""
+	}
+	return title
+}
+
+// ParseStatus parses the pane content and determines the OpenCode status.
+func (a *OpenCodeAgent) ParseStatus(content string) Status {
+	return parseOpenCodeStatus(content)
+}
@@ -0,0 +1,137 @@
+package agent
+
+import "testing"
+
+func TestOpenCodeAgent_MayBeProcess(t *testing.T) {
+	agent := &OpenCodeAgent{}
+	tests := []struct {
+		name           string
+		currentCommand string
+		want           bool
+	}{
+		{"OpenCode binary", "opencode", true},
+		{"OpenCode platform binary", "opencode-darwin-arm64", true},
+		{"Codex binary", "codex", false},
+		{"Node process", "node", false},
+		{"Zsh shell", "zsh", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.MayBeProcess(tt.currentCommand)
+			if got != tt.want {
+				t.Errorf("OpenCodeAgent.MayBeProcess(%q) = %v, want %v", tt.currentCommand, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenCodeAgent_ExtractSummary(t *testing.T) {
+	agent := &OpenCodeAgent{}
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"Default title", "opencode", ""},
+		{"Default title capitalized", "OpenCode", ""},
+		{"Session title", "OC | Fix login bug", "Fix login bug"},
+		{"Custom title", "Refactor parser", "Refactor parser"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ExtractSummary(tt.title)
+			if got != tt.want {
+				t.Errorf("OpenCodeAgent.ExtractSummary(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenCodeAgent_ParseStatus(t *testing.T) {
+	agent := &OpenCodeAgent{}
+	tests := []struct {
+		name      string
+		content   string
+		wantState string
+		wantMode  string
+	}{
+		{
+			name: "Idle with build agent",
+			content: `  Done. All tests pass.
+
+  ┃
+  ┃
+  ┃  Build  claude-sonnet-4-5 Anthropic
+                                         tab switch agent  ctrl+p commands`,
+			wantState: StateIdle,
+		},
+		{
+			name: "Idle with plan agent",
+			content: `  ┃
+  ┃  Plan  claude-sonnet-4-5 Anthropic
+                                         tab switch agent  ctrl+p commands`,
+			wantState: StateIdle,
+			wantMode:  ModePlan,
+		},
+		{
+			name: "Running with working indicator",
+			content: `  ┃
+  ┃  Build  claude-sonnet-4-5 Anthropic
+   ⬝⬝⬝■■■■  esc interrupt                tab switch agent  ctrl+p commands`,
+			wantState: StateRunning,
+		},
+		{
+			name: "Running in plan agent",
+			content: `  ┃  Plan  claude-sonnet-4-5 Anthropic
+   ⬝⬝■■■■⬝  esc interrupt                tab switch agent  ctrl+p commands`,
+			wantState: StateRunning,
+			wantMode:  ModePlan,
+		},
+		{
+			name: "Running with legacy working indicator",
+			content: `> Fix the login bug
+⠋ Working...                                                   PLAN AGENT`,
+			wantState: StateRunning,
+			wantMode:  ModePlan,
+		},
+		{
+			name: "Waiting with permission dialog",
+			content: `  △ Permission required
+  $ go test ./...
+
+   Allow once   Allow always   Reject               enter confirm`,
+			wantState: StateWaiting,
+		},
+		{
+			name: "Waiting with permission dialog over the working indicator",
+			content: `  △ Permission required
+  $ go test ./...
+
+   Allow once   Allow always   Reject               enter confirm
+
+  ⬝⬝⬝■■■■  esc interrupt`,
+			wantState: StateWaiting,
+		},
+		{
+			name: "Unknown state",
+			content: `Some random output
+without any recognizable pattern`,
+			wantState: StateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agent.ParseStatus(tt.content)
+			if got.State != tt.wantState {
+				t.Errorf("OpenCodeAgent.ParseStatus().State = %q, want %q", got.State, tt.wantState)
+			}
+			if got.Mode != tt.wantMode {
+				t.Errorf("OpenCodeAgent.ParseStatus().Mode = %q, want %q", got.Mode, tt.wantMode)
+			}
+		})
+	}
+}
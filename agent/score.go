@@ -0,0 +1,101 @@
+package agent
+
+import "sort"
+
+// Detection score weights
+const (
+	scoreTitleDistinct = 40 // Title has an agent-specific marker (e.g., "✳" prefix)
+	scoreTitleWeak     = 10 // Title is the agent's default title, but could be set by anything
+	scoreProcessArgs   = 50 // Command line runs the agent (resolved from the process tree)
+	scoreProcessExact  = 40 // Process name is the agent binary
+	scoreProcessShared = 10 // Process name is shared with other programs (e.g., "node")
+	scoreContent       = 30 // Pane content shows the agent's chrome (footer, prompt box, banner)
+)
+
+// Signals are the observations of a pane used to detect a coding agent.
+type Signals struct {
+	Title          string
+	CurrentCommand string
+	Args           []string // Command line of the pane's agent or foreground process (nil if not resolved)
+	Content        string   // Pane content (empty if not captured yet)
+}
+
+// Score is the detection confidence of a detector, broken down by signal.
+// A zero Score means the detector does not match.
+type Score struct {
+	Title   int
+	Process int
+	Content int
+}
+
+// Total returns the total confidence score.
+func (s Score) Total() int {
+	return s.Title + s.Process + s.Content
+}
+
+// Candidate is a detector with its detection confidence.
+type Candidate struct {
+	Detector Detector
+	Score    Score
+}
+
+// Candidates returns the detectors matching the signals, ordered by confidence (highest first).
+// Detectors with the same score keep the registration order.
+func Candidates(s Signals) []Candidate {
+	var candidates []Candidate
+	for _, d := range detectors {
+		score := d.Score(s)
+		if score.Total() <= 0 {
+			continue
+		}
+		candidates = append(candidates, Candidate{Detector: d, Score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score.Total() > candidates[j].Score.Total()
+	})
+	return candidates
+}
+
+// DetectSignals returns the detector with the highest confidence for the signals.
+// Returns nil if no agent is detected.
+func DetectSignals(s Signals) Detector {
+	candidates := Candidates(s)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0].Detector
+}
+
+// CandidatesByContent returns the detectors whose on-screen chrome is found in the pane content.
+// It is used as a second detection pass for panes without title or process signals.
+func CandidatesByContent(content string) []Candidate {
+	if content == "" {
+		return nil
+	}
+	var candidates []Candidate
+	for _, d := range detectors {
+		if cd, ok := d.(ContentDetector); ok && cd.MayBeContent(content) {
+			candidates = append(candidates, Candidate{Detector: d, Score: Score{Content: scoreContent}})
+		}
+	}
+	return candidates
+}
+
+// processScore scores the process signals with the detector's matchers.
+// When the command line is resolved, it is authoritative: a resolved process
+// that does not run the agent (e.g., "node server.js") scores zero.
+func processScore(s Signals, mayBeArgs func([]string) bool, exact func(string) bool, shared func(string) bool) int {
+	if s.Args != nil {
+		if mayBeArgs(s.Args) {
+			return scoreProcessArgs
+		}
+		return 0
+	}
+	switch {
+	case exact(s.CurrentCommand):
+		return scoreProcessExact
+	case shared != nil && shared(s.CurrentCommand):
+		return scoreProcessShared
+	}
+	return 0
+}
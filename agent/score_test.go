@@ -0,0 +1,175 @@
+package agent
+
+import "testing"
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name      string
+		signals   Signals
+		wantTypes []Type
+		wantTotal int
+	}{
+		{
+			name:      "Claude Code with title and node",
+			signals:   Signals{Title: "✳ Task summary", CurrentCommand: "node"},
+			wantTypes: []Type{TypeClaude},
+			wantTotal: scoreTitleDistinct + scoreProcessShared,
+		},
+		{
+			name: "Claude Code with content signature",
+			signals: Signals{Title: "✳ Task summary", CurrentCommand: "claude", Content: `❯
+  ? for shortcuts`},
+			wantTypes: []Type{TypeClaude},
+			wantTotal: scoreTitleDistinct + scoreProcessExact + scoreContent,
+		},
+		{
+			name:      "Claude Code resolved from command line without title",
+			signals:   Signals{Title: "zsh", CurrentCommand: "node", Args: []string{"node", "/usr/lib/node_modules/@anthropic-ai/claude-code/cli.js"}},
+			wantTypes: []Type{TypeClaude},
+			wantTotal: scoreProcessArgs,
+		},
+		{
+			name:      "Resolved node app is not an agent",
+			signals:   Signals{Title: "✳ Task summary", CurrentCommand: "node", Args: []string{"node", "server.js"}},
+			wantTypes: nil,
+		},
+		{
+			name:      "Gemini CLI with node",
+			signals:   Signals{Title: "◇  Ready (tcmux)", CurrentCommand: "node"},
+			wantTypes: []Type{TypeGemini},
+			wantTotal: scoreTitleDistinct + scoreProcessShared,
+		},
+		{
+			name:      "Codex CLI with default title",
+			signals:   Signals{Title: "Codex", CurrentCommand: "codex"},
+			wantTypes: []Type{TypeCodex},
+			wantTotal: scoreTitleWeak + scoreProcessExact,
+		},
+		{
+			name:      "Python without Aider content",
+			signals:   Signals{Title: "host", CurrentCommand: "python3", Content: ">>> "},
+			wantTypes: nil,
+		},
+		{
+			name:      "Normal shell",
+			signals:   Signals{Title: "zsh", CurrentCommand: "zsh"},
+			wantTypes: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Candidates(tt.signals)
+			if len(got) != len(tt.wantTypes) {
+				t.Fatalf("Candidates() returned %d candidates, want %d", len(got), len(tt.wantTypes))
+			}
+			for i, c := range got {
+				if c.Detector.Type() != tt.wantTypes[i] {
+					t.Errorf("Candidates()[%d].Type() = %v, want %v", i, c.Detector.Type(), tt.wantTypes[i])
+				}
+			}
+			if len(got) > 0 && got[0].Score.Total() != tt.wantTotal {
+				t.Errorf("Candidates()[0].Score.Total() = %d, want %d", got[0].Score.Total(), tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestCandidates_HighestScoreWins(t *testing.T) {
+	orig := detectors
+	t.Cleanup(func() { detectors = orig })
+
+	// A user-defined agent sharing the "node" process with a permissive title
+	a, err := NewCustomAgent(CustomAgentConfig{
+		Name:    "myagent",
+		Process: []string{`^node$`},
+		Idle:    []string{`(?m)^myagent> $`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Register(a)
+
+	// Without content, the Claude Code title gives the highest confidence...
+	s := Signals{Title: "✳ Task summary", CurrentCommand: "node"}
+	if got := DetectSignals(s); got == nil || got.Type() != TypeClaude {
+		t.Errorf("DetectSignals() = %v, want %v", got, TypeClaude)
+	}
+
+	// ...and the content signature raises the matching agent's confidence.
+	s.Content = "myagent> "
+	if got := DetectSignals(s); got == nil || got.Type() != "myagent" {
+		t.Errorf("DetectSignals() = %v, want myagent", got)
+	}
+}
+
+func TestCandidatesByContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantTypes []Type
+	}{
+		{
+			name: "Claude Code footer",
+			content: `───────────────────────────────────────
+❯
+───────────────────────────────────────
+  ? for shortcuts`,
+			wantTypes: []Type{TypeClaude},
+		},
+		{
+			name:      "Claude Code working indicator",
+			content:   "✢ Clauding… (esc to interrupt · 1m 45s · ↓ 1.2k tokens)",
+			wantTypes: []Type{TypeClaude},
+		},
+		{
+			name:      "Codex CLI working indicator is not Claude Code",
+			content:   "• Working (29s • esc to interrupt)",
+			wantTypes: nil,
+		},
+		{
+			name: "Copilot CLI prompt placeholder",
+			content: `───────────────────────────────────────
+❯  Type @ to mention files or / for commands
+───────────────────────────────────────
+ shift+tab cycle mode`,
+			wantTypes: []Type{TypeCopilot},
+		},
+		{
+			name: "Codex CLI footer",
+			content: `› Type a message
+  ⏎ send   ⌃J newline   ⌃T transcript   ⌃C quit   12.3K tokens used   88% context left`,
+			wantTypes: []Type{TypeCodex},
+		},
+		{
+			name: "Gemini CLI footer is not Codex",
+			content: `│ >   Type your message or @path/to/file   │
+~/src/tcmux (main*)   no sandbox (see /docs)   gemini-2.5-pro (97% context left)`,
+			wantTypes: []Type{TypeGemini},
+		},
+		{
+			name:      "Shell prompt",
+			content:   "$ ls\nREADME.md\n$ ",
+			wantTypes: nil,
+		},
+		{
+			name:      "Empty content",
+			content:   "",
+			wantTypes: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CandidatesByContent(tt.content)
+			if len(got) != len(tt.wantTypes) {
+				t.Fatalf("CandidatesByContent() returned %d candidates, want %d", len(got), len(tt.wantTypes))
+			}
+			for i, c := range got {
+				if c.Detector.Type() != tt.wantTypes[i] {
+					t.Errorf("CandidatesByContent()[%d].Type() = %v, want %v", i, c.Detector.Type(), tt.wantTypes[i])
+				}
+			}
+		})
+	}
+}
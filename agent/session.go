@@ -0,0 +1,25 @@
+package agent
+
+import "time"
+
+// sessionInfoTTL is how long session info is trusted without updates.
+const sessionInfoTTL = 24 * time.Hour
+
+// SessionInfo is the session metadata reported by an agent (e.g., via Claude Code's statusLine command).
+type SessionInfo struct {
+	SessionID    string    `json:"session_id"`
+	Model        string    `json:"model"`    // Display name of the model (e.g., "Opus")
+	ModelID      string    `json:"model_id"` // Model ID (e.g., "claude-opus-4-1")
+	Cwd          string    `json:"cwd"`
+	Version      string    `json:"version"`
+	CostUSD      float64   `json:"cost_usd"`
+	DurationMS   int64     `json:"duration_ms"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Fresh checks if the session info has been updated recently enough to be trusted.
+func (s *SessionInfo) Fresh(now time.Time) bool {
+	return s != nil && now.Sub(s.UpdatedAt) <= sessionInfoTTL
+}
@@ -1,6 +1,10 @@
 package agent
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // lastNonEmptyLines returns the last n non-empty lines.
 // It skips lines that are only separators (─, ═, etc.).
@@ -30,3 +34,31 @@ func isSeparatorLine(line string) bool {
 	}
 	return true
 }
+
+// FormatDuration formats a duration in the style of agent elapsed times (e.g., "45s", "3m", "2h 5m").
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		h := int(d.Hours())
+		m := int(d.Minutes()) % 60
+		if m == 0 {
+			return fmt.Sprintf("%dh", h)
+		}
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for line := range strings.SplitSeq(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Aider status indicators
+var (
+	// Prompt pattern: "> ", "architect> ", "ask> ", "diff multi> ", etc.
+	// The prefix is the chat mode or edit format, optionally followed by "multi".
+	aiderPromptPattern = regexp.MustCompile(`^(?:([a-z][a-z-]*)\s?)?(?:multi)?>(?:\s|$)`)
+
+	// Confirmation marker: "Run shell command? (Y)es/(N)o/(D)on't ask again [Yes]:"
+	aiderConfirmMarker = "(Y)es/(N)o"
+
+	// Running pattern: spinner shown while waiting for the LLM response
+	aiderRunningPattern = regexp.MustCompile(`Waiting for\s+\S+`)
+
+	// Signature pattern: lines only Aider prints (banner, model info, token report, etc.)
+	aiderSignaturePattern = regexp.MustCompile(`(?m)^(?:Aider v\d|(?:Main|Weak|Editor) model:|Repo-map:|Git repo:|Tokens: .+ sent|Added .+ to the chat|Use /help <question>|(?:architect|ask|help|context|code)>)`)
+)
+
+// Aider chat modes shown in the prompt prefix
+var aiderChatModes = map[string]string{
+	"architect": ModeArchitect,
+	"ask":       ModeAsk,
+	"help":      ModeHelp,
+	"context":   ModeContext,
+}
+
+// parseAiderStatus parses the pane content and determines the Aider status.
+func parseAiderStatus(content string) Status {
+	lines := strings.Split(content, "\n")
+	lastLines := lastNonEmptyLines(lines, 30)
+	combined := strings.Join(lastLines, "\n")
+
+	status := Status{
+		State:       StateUnknown,
+		Mode:        "",
+		Description: "",
+	}
+
+	// The mode is taken from the last prompt shown on the screen
+	promptSeen := false
+	for i := len(lastLines) - 1; i >= 0; i-- {
+		if matches := aiderPromptPattern.FindStringSubmatch(strings.TrimSpace(lastLines[i])); len(matches) > 0 {
+			status.Mode = aiderChatMode(matches[1])
+			promptSeen = true
+			break
+		}
+	}
+
+	if len(lastLines) == 0 {
+		return status
+	}
+	last := strings.TrimSpace(lastLines[len(lastLines)-1])
+
+	// Check for waiting state: confirmation on the last line
+	if strings.Contains(last, aiderConfirmMarker) {
+		status.State = StateWaiting
+		return status
+	}
+
+	// Check for idle state: the last line is an (empty) prompt
+	if aiderPromptPattern.MatchString(last) {
+		status.State = StateIdle
+		return status
+	}
+
+	// Check for running state: spinner while waiting for the LLM
+	if aiderRunningPattern.MatchString(last) {
+		status.State = StateRunning
+		return status
+	}
+
+	// Streaming output below a submitted prompt
+	if promptSeen || aiderSignaturePattern.MatchString(combined) {
+		status.State = StateRunning
+		return status
+	}
+
+	return status
+}
+
+// aiderChatMode maps the prompt prefix to a chat mode.
+// Any other prefix is an edit format, which means code mode.
+func aiderChatMode(prefix string) string {
+	if mode, ok := aiderChatModes[prefix]; ok {
+		return mode
+	}
+	return ModeCode
+}
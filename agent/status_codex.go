@@ -10,6 +10,9 @@ var (
 	// Running patterns.
 	codexRunningParenPattern = regexp.MustCompile(`(?i)\([^)]*(Esc to cancel|esc to interrupt|ctrl\+c to interrupt)`)
 
+	// Elapsed time in the working indicator: "• Working (1m 29s • esc to interrupt)"
+	codexElapsedPattern = regexp.MustCompile(`\(((?:\d+[smh]\s*)+)[•·]`)
+
 	// Waiting patterns.
 	codexWaitingPatterns = []string{
 		"Do you want to run this command?",
@@ -46,6 +49,9 @@ func parseCodexStatus(content string) Status {
 
 	if codexRunningParenPattern.MatchString(combined) {
 		status.State = StateRunning
+		if matches := codexElapsedPattern.FindStringSubmatch(combined); len(matches) > 0 {
+			status.Description = strings.TrimSpace(matches[1]) // Time elapsed
+		}
 		return status
 	}
 
@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Gemini CLI status indicators
+var (
+	// Running pattern: loading indicator with elapsed time
+	// Format: ⠏ Reticulating splines (esc to cancel, 1m 5s)
+	geminiRunningPattern = regexp.MustCompile(`\(esc to cancel, ((?:\d+[smh]\s*)+)\)`)
+
+	// Running fallback pattern without elapsed time
+	geminiRunningFallbackPattern = regexp.MustCompile(`\(esc to cancel\b`)
+
+	// Waiting patterns: tool confirmation dialogs
+	geminiWaitingPatterns = []string{
+		"Allow execution of",
+		"Apply this change?",
+		"Do you want to proceed?",
+		"Yes, allow once",
+		"Yes, allow always",
+		"Modify with external editor",
+		"No, suggest changes",
+		"Waiting for user confirmation",
+	}
+
+	// Accept edits pattern: "accepting edits (shift + tab to toggle)"
+	geminiAcceptEditsPattern = regexp.MustCompile(`accepting edits\s*\(`)
+
+	// Footer pattern: "gemini-2.5-pro (98% context left)"
+	geminiContextLeftPattern = regexp.MustCompile(`\(\d{1,3}% context left\)`)
+)
+
+// parseGeminiStatus parses the pane content and determines the Gemini CLI status.
+func parseGeminiStatus(content string) Status {
+	lines := strings.Split(content, "\n")
+
+	// Check the last few lines for status indicators
+	lastLines := lastNonEmptyLines(lines, 30)
+	combined := strings.Join(lastLines, "\n")
+
+	status := Status{
+		State:       StateUnknown,
+		Mode:        "",
+		Description: "",
+	}
+
+	if geminiAcceptEditsPattern.MatchString(combined) {
+		status.Mode = ModeAcceptEdits
+	}
+
+	// Check for running state (with elapsed time)
+	if matches := geminiRunningPattern.FindStringSubmatch(combined); len(matches) > 0 {
+		status.State = StateRunning
+		status.Description = strings.TrimSpace(matches[1]) // Time elapsed
+		return status
+	}
+
+	if geminiRunningFallbackPattern.MatchString(combined) {
+		status.State = StateRunning
+		return status
+	}
+
+	// Check for idle state: the input box is the last meaningful element
+	if isGeminiPromptLine(lines) {
+		status.State = StateIdle
+		return status
+	}
+
+	// Check for waiting state: tool confirmation dialogs
+	for _, pattern := range geminiWaitingPatterns {
+		if strings.Contains(combined, pattern) {
+			status.State = StateWaiting
+			return status
+		}
+	}
+
+	return status
+}
+
+// isGeminiPromptLine checks if the last meaningful element is the input box.
+// Gemini CLI draws its input box with rounded borders:
+//
+//	╭──────────────────────────────────────────╮
+//	│ >   Type your message or @path/to/file   │
+//	╰──────────────────────────────────────────╯
+func isGeminiPromptLine(lines []string) bool {
+	inBox := false
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if isSeparatorLine(line) {
+			if inBox {
+				// Reached the top border without finding a prompt
+				return false
+			}
+			continue
+		}
+		if !inBox {
+			// Skip footer lines below the input box
+			if geminiContextLeftPattern.MatchString(line) ||
+				strings.Contains(line, "sandbox") ||
+				strings.Contains(line, "ctrl + ") ||
+				strings.Contains(line, "shift + ") {
+				continue
+			}
+		}
+		if !strings.HasPrefix(line, "│") {
+			return false
+		}
+		inBox = true
+		// Strip the box borders and check for the prompt marker.
+		// Multi-line input continues upward until the line with the marker.
+		inner := strings.TrimSpace(strings.Trim(line, "│"))
+		if strings.HasPrefix(inner, ">") || strings.HasPrefix(inner, "!") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OpenCode status indicators
+var (
+	// Running patterns: working indicator next to the input box
+	// e.g., "⬝⬝⬝■■■■  esc interrupt", "Working...  press esc to interrupt"
+	opencodeRunningPattern = regexp.MustCompile(`(?i)(\besc\s+(?:to\s+)?interrupt\b|^\s*\S?\s*working(?:\.\.\.|…))`)
+
+	// Waiting patterns: permission dialogs
+	opencodeWaitingPatterns = []string{
+		"Permission required",
+		"Allow once",
+		"Allow always",
+	}
+
+	// Agent switcher pattern: "Build  claude-sonnet-4-5 Anthropic", "PLAN AGENT"
+	opencodeAgentPattern       = regexp.MustCompile(`(?m)^\s*[┃│]?\s*(Build|Plan)\s{2,}\S`)
+	opencodeLegacyAgentPattern = regexp.MustCompile(`(?i)\b(build|plan)\s+agent\b`)
+
+	// Idle patterns: input box hints
+	opencodeIdlePatterns = []string{
+		"enter send",
+		"tab switch agent",
+		"ctrl+p commands",
+		"ctrl+x h help",
+	}
+)
+
+// parseOpenCodeStatus parses the pane content and determines the OpenCode status.
+func parseOpenCodeStatus(content string) Status {
+	lines := strings.Split(content, "\n")
+	lastLines := lastNonEmptyLines(lines, 30)
+	combined := strings.Join(lastLines, "\n")
+
+	status := Status{
+		State:       StateUnknown,
+		Mode:        "",
+		Description: "",
+	}
+
+	if detectOpenCodeAgent(lastLines) == "plan" {
+		status.Mode = ModePlan
+	}
+
+	// OpenCode is a full-screen TUI that redraws the whole screen,
+	// so a visible permission dialog is always current (unlike scrollback-based CLIs).
+	// The working indicator stays on screen while the dialog is shown, so Waiting is checked first.
+	for _, pattern := range opencodeWaitingPatterns {
+		if strings.Contains(combined, pattern) {
+			status.State = StateWaiting
+			return status
+		}
+	}
+
+	for _, line := range lastLines {
+		if opencodeRunningPattern.MatchString(line) {
+			status.State = StateRunning
+			return status
+		}
+	}
+
+	for _, pattern := range opencodeIdlePatterns {
+		if strings.Contains(combined, pattern) {
+			status.State = StateIdle
+			return status
+		}
+	}
+
+	return status
+}
+
+// detectOpenCodeAgent returns the lowercased name of the active primary agent (build or plan).
+func detectOpenCodeAgent(lines []string) string {
+	agent := ""
+	for _, line := range lines {
+		if matches := opencodeAgentPattern.FindStringSubmatch(line); len(matches) > 0 {
+			agent = strings.ToLower(matches[1])
+			continue
+		}
+		if matches := opencodeLegacyAgentPattern.FindStringSubmatch(line); len(matches) > 0 {
+			agent = strings.ToLower(matches[1])
+		}
+	}
+	return agent
+}
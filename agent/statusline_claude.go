@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// claudeStatusLineInput is the JSON payload Claude Code passes to the statusLine command on stdin.
+type claudeStatusLineInput struct {
+	SessionID string `json:"session_id"`
+	Cwd       string `json:"cwd"`
+	Version   string `json:"version"`
+	Model     struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"model"`
+	Workspace struct {
+		CurrentDir string `json:"current_dir"`
+	} `json:"workspace"`
+	Cost struct {
+		TotalCostUSD      float64 `json:"total_cost_usd"`
+		TotalDurationMS   int64   `json:"total_duration_ms"`
+		TotalLinesAdded   int     `json:"total_lines_added"`
+		TotalLinesRemoved int     `json:"total_lines_removed"`
+	} `json:"cost"`
+}
+
+// ParseStatusLine parses the JSON payload of Claude Code's statusLine command into session info.
+func (a *ClaudeAgent) ParseStatusLine(payload []byte, now time.Time) (*SessionInfo, error) {
+	var in claudeStatusLineInput
+	if err := json.Unmarshal(payload, &in); err != nil {
+		return nil, fmt.Errorf("invalid statusLine payload: %w", err)
+	}
+	cwd := in.Workspace.CurrentDir
+	if cwd == "" {
+		cwd = in.Cwd
+	}
+	return &SessionInfo{
+		SessionID:    in.SessionID,
+		Model:        in.Model.DisplayName,
+		ModelID:      in.Model.ID,
+		Cwd:          cwd,
+		Version:      in.Version,
+		CostUSD:      in.Cost.TotalCostUSD,
+		DurationMS:   in.Cost.TotalDurationMS,
+		LinesAdded:   in.Cost.TotalLinesAdded,
+		LinesRemoved: in.Cost.TotalLinesRemoved,
+		UpdatedAt:    now,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaudeAgent_ParseStatusLine(t *testing.T) {
+	agent := &ClaudeAgent{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		payload string
+		want    *SessionInfo
+		wantErr bool
+	}{
+		{
+			name:    "Full payload",
+			payload: `{"hook_event_name":"Status","session_id":"abc123","cwd":"/tmp","model":{"id":"claude-opus-4-1","display_name":"Opus"},"workspace":{"current_dir":"/home/user/src","project_dir":"/home/user"},"version":"1.0.80","cost":{"total_cost_usd":0.01234,"total_duration_ms":45000,"total_api_duration_ms":2300,"total_lines_added":156,"total_lines_removed":23}}`,
+			want: &SessionInfo{
+				SessionID:    "abc123",
+				Model:        "Opus",
+				ModelID:      "claude-opus-4-1",
+				Cwd:          "/home/user/src",
+				Version:      "1.0.80",
+				CostUSD:      0.01234,
+				DurationMS:   45000,
+				LinesAdded:   156,
+				LinesRemoved: 23,
+				UpdatedAt:    now,
+			},
+		},
+		{
+			name:    "Falls back to cwd without workspace",
+			payload: `{"session_id":"abc123","cwd":"/tmp","model":{"display_name":"Sonnet"}}`,
+			want: &SessionInfo{
+				SessionID: "abc123",
+				Model:     "Sonnet",
+				Cwd:       "/tmp",
+				UpdatedAt: now,
+			},
+		},
+		{
+			name:    "Invalid JSON",
+			payload: `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := agent.ParseStatusLine([]byte(tt.payload), now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStatusLine() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseStatusLine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
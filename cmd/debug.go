@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/mux"
+	"github.com/spf13/cobra"
+)
+
+var debugAllSessions bool
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Show coding agent detection details for each tmux pane",
+	Long:  `Show coding agent detection details (signals and confidence scores of each candidate agent) for each tmux pane.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		defer connectTmux(ctx)()
+
+		panes, err := multiplexer.ListPanes(ctx, mux.InternalPaneVars, mux.ListOptions{AllSessions: debugAllSessions})
+		if err != nil {
+			return fmt.Errorf("failed to list %s panes: %w", multiplexer.Name(), err)
+		}
+
+		detector := newPaneDetector()
+		for _, pane := range panes {
+			s, _ := detector.signals(pane.Vars)
+			fmt.Printf("%s %s:%s command=%q title=%q\n",
+				pane.Vars["pane_id"], pane.Vars["session_name"], pane.Vars["window_index"], s.CurrentCommand, s.Title)
+			if s.Args != nil {
+				fmt.Printf("  args=%q\n", strings.Join(s.Args, " "))
+			}
+			contentOnly := agent.DetectSignals(s) == nil
+			if contentOnly && (!scanContent || shells[s.CurrentCommand]) {
+				continue
+			}
+
+			content, err := capturePane(ctx, pane.Vars)
+			if err != nil {
+				fmt.Printf("  capture failed: %v\n", err)
+				continue
+			}
+			s.Content = content
+
+			candidates := agent.Candidates(s)
+			if contentOnly {
+				candidates = agent.CandidatesByContent(content)
+			}
+			for i, c := range candidates {
+				marker := " "
+				if i == 0 {
+					marker = "*"
+				}
+				status := c.Detector.ParseStatus(content)
+				fmt.Printf("  %s %s %-10s score=%-3d (title=%d process=%d content=%d) [%s]\n",
+					marker, c.Detector.Icon(), c.Detector.Type(), c.Score.Total(),
+					c.Score.Title, c.Score.Process, c.Score.Content, status.State)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	debugCmd.Flags().BoolVarP(&debugAllSessions, "all-sessions", "a", false, "Show panes from all sessions")
+	rootCmd.AddCommand(debugCmd)
+}
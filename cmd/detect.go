@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+	"github.com/k1LoW/tcmux/history"
+	"github.com/k1LoW/tcmux/hook"
+	"github.com/k1LoW/tcmux/mux"
+	"github.com/k1LoW/tcmux/output"
+	"github.com/k1LoW/tcmux/proc"
+	"github.com/k1LoW/tcmux/tmux"
+)
+
+// shells are processes that are never coding agents.
+// A pane running a shell is at its prompt, so the content-only pass skips it.
+var shells = map[string]bool{
+	"bash": true,
+	"dash": true,
+	"fish": true,
+	"ksh":  true,
+	"nu":   true,
+	"pwsh": true,
+	"sh":   true,
+	"tcsh": true,
+	"zsh":  true,
+}
+
+const (
+	// captureLinesAboveCursor is the number of lines above the cursor to capture for detection.
+	// The status parsers look at the last 30 non-empty lines; the lines below the cursor (e.g., footers) are always captured.
+	captureLinesAboveCursor = 50
+	// captureTimeout is the timeout to capture a pane.
+	captureTimeout = 2 * time.Second
+	// maxConcurrentInspections is the maximum number of panes inspected concurrently.
+	maxConcurrentInspections = 8
+)
+
+// paneDetector detects coding agents running in tmux panes.
+type paneDetector struct {
+	procs       *proc.Table    // nil if the process table is not available
+	hooks       *hook.Store    // Hook records reported by agents
+	history     *history.Store // State histories of agents
+	scanContent bool           // Capture panes without title or process signals (second pass)
+}
+
+// paneAgent is a coding agent detected in a pane.
+type paneAgent struct {
+	detector agent.Detector
+	score    agent.Score
+	status   agent.Status
+	since    time.Time // When the current state was first observed
+	summary  string
+	cmdline  string             // Resolved command line of the agent process (empty if not resolved)
+	session  *agent.SessionInfo // Session info reported by the agent (nil if not reported)
+}
+
+// newPaneDetector creates a paneDetector with a snapshot of the process table.
+func newPaneDetector() *paneDetector {
+	procs, err := proc.Snapshot()
+	if err != nil {
+		// Fall back to pane_title and pane_current_command only
+		procs = nil
+	}
+	return &paneDetector{
+		procs:       procs,
+		hooks:       hook.Default(),
+		history:     history.Default(),
+		scanContent: scanContent,
+	}
+}
+
+// signals collects the detection signals of the pane (without content).
+// It walks the process tree from pane_pid to find the agent process by its command line,
+// so agents launched through wrappers (npx, uv run, scripts, etc.) are detected.
+// If no agent process is found, the command line of the foreground process is used
+// and the returned agent process is nil.
+func (pd *paneDetector) signals(vars map[string]string) (agent.Signals, *proc.Process) {
+	s := agent.Signals{
+		Title:          vars["pane_title"],
+		CurrentCommand: vars["pane_current_command"],
+	}
+	pid, err := strconv.Atoi(vars["pane_pid"])
+	if pd.procs == nil || err != nil {
+		// Use the command line reported by the multiplexer if any
+		if cmdline := strings.Fields(vars[mux.VarPaneCommand]); len(cmdline) > 0 {
+			s.Args = cmdline
+		}
+		return s, nil
+	}
+	var foreground *proc.Process
+	for _, p := range pd.procs.Tree(pid) {
+		if agent.DetectArgs(p.Args) != nil {
+			s.Args = p.Args
+			return s, p
+		}
+		if p.Comm == s.CurrentCommand || (len(p.Args) > 0 && filepath.Base(p.Args[0]) == s.CurrentCommand) {
+			foreground = p
+		}
+	}
+	if foreground != nil && len(foreground.Args) > 0 {
+		s.Args = foreground.Args
+	}
+	return s, nil
+}
+
+// historyKey returns the key of the state history of the agent in the pane.
+// The start time of the agent process is used if resolved, otherwise that of the pane process.
+func (pd *paneDetector) historyKey(vars map[string]string, p *proc.Process) history.Key {
+	key := history.Key{PaneID: paneKey(vars)}
+	if p == nil && pd.procs != nil {
+		if pid, err := strconv.Atoi(vars["pane_pid"]); err == nil {
+			p = pd.procs.Get(pid)
+		}
+	}
+	if p != nil {
+		key.StartTime = p.StartTime
+	}
+	return key
+}
+
+// inspect detects a coding agent in the pane and parses its status.
+// Candidates are first scored by title and process; if any matches, the pane is
+// captured and rescored with its content, and the most confident agent is used.
+// If none matches and content scanning is enabled, the pane is captured and
+// agents are recognised by their on-screen chrome alone (second pass).
+// prev is the agent detected in the pane by the previous inspection (nil if none); it is kept
+// if the pane cannot be captured or the state is unknown while the pane still runs the agent,
+// so transient failures are not reported as the agent disappearing and appearing again.
+// Returns nil if no agent is detected, or if the pane cannot be captured or the state is unknown without prev.
+func (pd *paneDetector) inspect(ctx context.Context, vars map[string]string, prev *paneAgent) *paneAgent {
+	s, p := pd.signals(vars)
+	contentOnly := agent.DetectSignals(s) == nil
+	if contentOnly && (!pd.scanContent || shells[s.CurrentCommand]) {
+		return nil
+	}
+
+	content, err := capturePane(ctx, vars)
+	if err != nil {
+		if contentOnly {
+			// Agents detected by content alone cannot be checked without the content
+			return prev
+		}
+		return carryForward(prev, agent.DetectSignals(s))
+	}
+	s.Content = content
+
+	var candidates []agent.Candidate
+	if contentOnly {
+		candidates = agent.CandidatesByContent(content)
+	} else {
+		candidates = agent.Candidates(s)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	d := candidates[0].Detector
+
+	now := time.Now()
+	status := d.ParseStatus(content)
+	if hr, ok := d.(agent.HookResolver); ok {
+		// Prefer the state reported by the agent's hooks
+		if rec, err := pd.hooks.Load(d.Type(), paneKey(vars)); err == nil {
+			status = hr.ResolveStatus(status, rec, now)
+		}
+	}
+	if status.State == agent.StateUnknown {
+		return carryForward(prev, d)
+	}
+
+	// Record the state to know how long the agent has been in it
+	since := now
+	if e, err := pd.history.Observe(pd.historyKey(vars, p), d.Type(), status, now); err == nil {
+		since = e.Since
+	}
+
+	session, err := pd.hooks.LoadSession(d.Type(), paneKey(vars))
+	if err != nil || !session.Fresh(now) {
+		session = nil
+	}
+
+	summary := ""
+	if d.MayBeTitle(s.Title) {
+		summary = d.ExtractSummary(s.Title)
+	}
+
+	cmdline := ""
+	if p != nil {
+		cmdline = p.Cmdline()
+	}
+
+	return &paneAgent{
+		detector: d,
+		score:    candidates[0].Score,
+		status:   status,
+		since:    since,
+		summary:  summary,
+		cmdline:  cmdline,
+		session:  session,
+	}
+}
+
+// carryForward returns the previously detected agent if it is of the type of the detector
+// (nil if either is nil).
+func carryForward(prev *paneAgent, d agent.Detector) *paneAgent {
+	if prev == nil || d == nil || prev.detector.Type() != d.Type() {
+		return nil
+	}
+	return prev
+}
+
+// inspectAll inspects the panes concurrently and returns the detected agents in the order of the panes
+// (nil for panes without agents). prev returns the agent previously detected in the pane (nil if none
+// or if prev is nil; see inspect).
+func (pd *paneDetector) inspectAll(ctx context.Context, panes []mux.Pane, prev func(mux.Pane) *paneAgent) []*paneAgent {
+	agents := make([]*paneAgent, len(panes))
+	sem := make(chan struct{}, maxConcurrentInspections)
+	var wg sync.WaitGroup
+	for i, pane := range panes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			var p *paneAgent
+			if prev != nil {
+				p = prev(pane)
+			}
+			agents[i] = pd.inspect(ctx, pane.Vars, p)
+		}()
+	}
+	wg.Wait()
+	return agents
+}
+
+// capturePane captures the tail of the pane needed for detection, from some lines above the cursor
+// to the bottom of the screen.
+func capturePane(ctx context.Context, vars map[string]string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout)
+	defer cancel()
+	var opts mux.CaptureOptions
+	if y, err := strconv.Atoi(vars["cursor_y"]); err == nil {
+		opts.Start = strconv.Itoa(max(y-captureLinesAboveCursor, 0))
+	}
+	return multiplexer.CapturePane(ctx, mux.Pane{Vars: vars}, opts)
+}
+
+// paneKey returns the key of the pane in records of panes (hook records and state histories),
+// which is unique across sessions and servers.
+func paneKey(vars map[string]string) string {
+	return multiplexer.PaneKey(mux.Pane{Vars: vars})
+}
+
+// agentPane is a coding agent detected in a tmux pane.
+type agentPane struct {
+	*paneAgent
+	vars map[string]string // Variables of the pane (mux.InternalPaneVars)
+}
+
+// agentInfo converts the detected agent in the pane to output.AgentInfo.
+func (pa *paneAgent) agentInfo(paneID string) output.AgentInfo {
+	return output.AgentInfo{
+		PaneID:    paneID,
+		AgentType: pa.detector.Type(),
+		Icon:      pa.detector.Icon(),
+		Summary:   pa.summary,
+		Status:    pa.status,
+		Since:     pa.since,
+		Cmdline:   pa.cmdline,
+		Session:   pa.session,
+	}
+}
+
+// scanAgents detects coding agents in the panes of all sessions.
+func scanAgents(ctx context.Context) ([]agentPane, error) {
+	panes, err := listAllPanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var agents []agentPane
+	for i, pa := range newPaneDetector().inspectAll(ctx, panes, nil) {
+		if pa != nil {
+			agents = append(agents, agentPane{paneAgent: pa, vars: panes[i].Vars})
+		}
+	}
+	return agents, nil
+}
+
+// listAllPanes lists the panes of all sessions with the variables required for detection.
+func listAllPanes(ctx context.Context) ([]mux.Pane, error) {
+	if usingTmux() && controlMode {
+		// Reconnect if the control-mode connection was lost (e.g., the tmux server restarted)
+		_ = tmux.Connect(ctx)
+	}
+
+	panes, err := multiplexer.ListPanes(ctx, mux.InternalPaneVars, mux.ListOptions{AllSessions: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s panes: %w", multiplexer.Name(), err)
+	}
+	return panes, nil
+}
+
+// eventAgents converts detected agents to the observations of the event package.
+func eventAgents(agents []agentPane) []event.Agent {
+	eas := make([]event.Agent, 0, len(agents))
+	for _, a := range agents {
+		eas = append(eas, event.Agent{
+			PaneID:       a.vars["pane_id"],
+			ServerSocket: a.vars[tmux.VarServerSocket],
+			Session:      a.vars["session_name"],
+			WindowIndex:  a.vars["window_index"],
+			WindowName:   a.vars["window_name"],
+			Type:         a.detector.Type(),
+			Icon:         a.detector.Icon(),
+			Summary:      a.summary,
+			Status:       a.status,
+			Since:        a.since,
+		})
+	}
+	return eas
+}
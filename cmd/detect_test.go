@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+)
+
+func TestCarryForward(t *testing.T) {
+	claude := &agent.ClaudeAgent{}
+	codex := &agent.CodexAgent{}
+	running := &paneAgent{detector: claude, status: agent.Status{State: agent.StateRunning}}
+	tests := []struct {
+		name string
+		prev *paneAgent
+		d    agent.Detector
+		want *paneAgent
+	}{
+		{"Same agent", running, claude, running},
+		{"Different agent", running, codex, nil},
+		{"No agent in the pane", running, nil, nil},
+		{"Not detected before", nil, claude, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := carryForward(tt.prev, tt.d); got != tt.want {
+				t.Errorf("carryForward() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCarryForward_Diff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	claude := &agent.ClaudeAgent{}
+	vars := map[string]string{"pane_id": "%1", "session_name": "dev"}
+	running := &paneAgent{detector: claude, status: agent.Status{State: agent.StateRunning}}
+	idle := &paneAgent{detector: claude, status: agent.Status{State: agent.StateIdle}}
+
+	// Running, then a capture failure or an unknown state, then Idle
+	observations := []*paneAgent{running, carryForward(running, claude), idle}
+	want := [][]event.Kind{nil, {event.KindState}}
+
+	prev := eventAgents([]agentPane{{paneAgent: observations[0], vars: vars}})
+	for i, pa := range observations[1:] {
+		if pa == nil {
+			t.Fatalf("observation %d is nil", i+1)
+		}
+		cur := eventAgents([]agentPane{{paneAgent: pa, vars: vars}})
+		var got []event.Kind
+		for _, e := range event.Diff(prev, cur, now) {
+			got = append(got, e.Kind)
+		}
+		if !slices.Equal(got, want[i]) {
+			t.Errorf("events of observation %d = %v, want %v", i+1, got, want[i])
+		}
+		prev = cur
+	}
+}
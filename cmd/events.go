@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/tcmux/event"
+	"github.com/spf13/cobra"
+)
+
+var eventsInterval time.Duration
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream coding agent changes as NDJSON",
+	Long: `Watch coding agent instances (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode) in all tmux sessions and write one JSON object per line for each change.
+
+Event kinds:
+  appeared     A coding agent is detected in a pane (also reported for the agents found at start)
+  state        The state of a coding agent changed
+  mode         The mode of a coding agent changed
+  summary      The summary (pane title) of a coding agent changed
+  disappeared  A coding agent is no longer detected in a pane`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if eventsInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", eventsInterval)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		defer connectTmux(ctx)()
+
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetEscapeHTML(false)
+		return monitorEvents(ctx, eventsInterval, true, cmd.ErrOrStderr(), func(events []event.Event) error {
+			for _, e := range events {
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	eventsCmd.Flags().DurationVarP(&eventsInterval, "interval", "n", 2*time.Second, "Polling interval if changes in tmux are not watched")
+	addWatchTmuxFlag(eventsCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/hook"
+	"github.com/k1LoW/tcmux/tmux"
+	"github.com/k1LoW/tcmux/zellij"
+	"github.com/spf13/cobra"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Record coding agent hook events for the current tmux pane",
+	Long:  `Record coding agent hook events for the current tmux pane ($TMUX_PANE), so that tcmux can use the exact agent state instead of parsing the screen.`,
+	// Run by the agents' hooks, which must not fail because of agents.yaml
+	Annotations: map[string]string{annotationNoDetection: ""},
+}
+
+var hookClaudeCmd = &cobra.Command{
+	Use:   "claude",
+	Short: "Record a Claude Code hook event",
+	Long: `Record a Claude Code hook event read from stdin.
+
+Register this command in Claude Code's hooks (UserPromptSubmit, PreToolUse, PostToolUse, Notification, Stop, SessionStart and SessionEnd).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paneID := currentPaneKey()
+		if paneID == "" {
+			// Not running in tmux
+			return nil
+		}
+
+		payload, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read hook payload: %w", err)
+		}
+
+		a := &agent.ClaudeAgent{}
+		rec, err := a.ParseHook(payload, paneID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		store := hook.Default()
+		if rec.Event == agent.ClaudeHookSessionEnd {
+			if err := store.RemoveSession(rec.Agent, paneID); err != nil {
+				return err
+			}
+			return store.Remove(rec.Agent, paneID)
+		}
+		return store.Save(rec)
+	},
+}
+
+var hookCodexCmd = &cobra.Command{
+	Use:   "codex [PAYLOAD]",
+	Short: "Record a Codex CLI notify event",
+	Long: `Record a Codex CLI notify event.
+
+Codex CLI passes the JSON payload as the last argument of the notify program; if no argument is given, it is read from stdin.
+Set this command as notify in Codex CLI's config.toml: notify = ["tcmux", "hook", "codex"]`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paneID := currentPaneKey()
+		if paneID == "" {
+			// Not running in tmux
+			return nil
+		}
+
+		var payload []byte
+		if len(args) > 0 {
+			payload = []byte(args[0])
+		} else {
+			var err error
+			payload, err = io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("failed to read notify payload: %w", err)
+			}
+		}
+
+		a := &agent.CodexAgent{}
+		rec, err := a.ParseHook(payload, paneID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return hook.Default().Save(rec)
+	},
+}
+
+func init() {
+	hookCmd.AddCommand(hookClaudeCmd)
+	hookCmd.AddCommand(hookCodexCmd)
+	rootCmd.AddCommand(hookCmd)
+}
+
+// currentPaneKey returns the key of the current tmux pane ($TMUX_PANE) or Zellij pane in records of panes,
+// which is unique across sessions and servers. Returns "" if not running in tmux or Zellij.
+func currentPaneKey() string {
+	if paneID := os.Getenv("TMUX_PANE"); paneID != "" {
+		return tmux.PaneKey(tmux.DefaultSocket(), paneID)
+	}
+	return zellij.CurrentPaneKey()
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"regexp"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/mux"
 	"github.com/k1LoW/tcmux/output"
 	"github.com/k1LoW/tcmux/tmux"
 	"github.com/spf13/cobra"
@@ -19,8 +22,16 @@ var lsCmd = &cobra.Command{
 	Use:     "list-sessions",
 	Aliases: []string{"ls"},
 	Short:   "List tmux sessions with coding agent status",
-	Long:    `List tmux sessions with coding agent status (Claude Code, Copilot CLI, and Codex CLI).`,
+	Long:    `List tmux sessions with coding agent status (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := output.ValidateStructuredFormat(outputFormat); err != nil {
+			return err
+		}
+		filter, err := agentFilter()
+		if err != nil {
+			return err
+		}
+
 		// Use format string if specified, otherwise use default
 		format := lsFormat
 		if format == "" {
@@ -31,61 +42,50 @@ var lsCmd = &cobra.Command{
 		userVars := output.ExtractTmuxVars(format)
 
 		// Build combined variable list
-		allVars := mergeVars(userVars, tmux.InternalSessionVars)
-
-		// Build tmux format string
-		tmuxFormat := buildTmuxFormat(allVars)
+		allVars := mergeVars(userVars, mux.InternalSessionVars)
 
 		ctx := cmd.Context()
-		sessions, err := tmux.ListSessions(ctx, tmuxFormat, allVars)
+		defer connectTmux(ctx)()
+		sessions, err := multiplexer.ListSessions(ctx, allVars)
 		if err != nil {
-			return fmt.Errorf("failed to list tmux sessions: %w", err)
+			return fmt.Errorf("failed to list %s sessions: %w", multiplexer.Name(), err)
 		}
+		sessions = slices.DeleteFunc(sessions, func(s mux.Session) bool {
+			return !filter.MatchSession(s.Vars["session_name"])
+		})
 
-		if len(sessions) == 0 {
+		if len(sessions) == 0 && outputFormat == "" {
 			fmt.Println("No tmux sessions found.")
 			return nil
 		}
 
 		// Get all panes to count coding agent instances per session
-		paneFormat := buildTmuxFormat(tmux.InternalPaneVars)
-		panes, err := tmux.ListPanes(ctx, paneFormat, tmux.InternalPaneVars, tmux.ListPanesOptions{AllSessions: true})
+		panes, err := multiplexer.ListPanes(ctx, mux.InternalPaneVars, mux.ListOptions{AllSessions: true})
 		if err != nil {
-			return fmt.Errorf("failed to list tmux panes: %w", err)
+			return fmt.Errorf("failed to list %s panes: %w", multiplexer.Name(), err)
 		}
 
 		// Build session stats
 		sessionStats := make(map[string]*output.SessionFormatContext)
 		for _, session := range sessions {
-			sessionName := session.Vars["session_name"]
-			sessionStats[sessionName] = &output.SessionFormatContext{
+			sessionStats[sessionKey(session.Vars)] = &output.SessionFormatContext{
 				TmuxVars: session.Vars,
 			}
 		}
 
 		// Count coding agent instances per session
-		for _, pane := range panes {
-			stats, ok := sessionStats[pane.Vars["session_name"]]
-			if !ok {
-				continue
-			}
-
-			detectedAgent := agent.Detect(pane.Vars["pane_title"], pane.Vars["pane_current_command"])
-			if detectedAgent == nil {
-				continue
-			}
-
-			content, err := tmux.CapturePane(ctx, pane.Vars["pane_id"])
-			if err != nil {
-				continue
-			}
-
-			status := detectedAgent.ParseStatus(content)
-			if status.State == agent.StateUnknown {
+		panes = slices.DeleteFunc(panes, func(pane mux.Pane) bool {
+			_, ok := sessionStats[sessionKey(pane.Vars)]
+			return !ok
+		})
+		for i, pa := range newPaneDetector().inspectAll(ctx, panes, nil) {
+			pane := panes[i]
+			stats := sessionStats[sessionKey(pane.Vars)]
+			if pa == nil || !filter.Match(pa.agentInfo(pane.Vars["pane_id"]), pane.Vars["session_name"]) {
 				continue
 			}
 
-			switch status.State {
+			switch pa.status.State {
 			case agent.StateIdle:
 				stats.IdleCount++
 			case agent.StateRunning:
@@ -95,14 +95,30 @@ var lsCmd = &cobra.Command{
 			}
 		}
 
+		if outputFormat != "" {
+			var data []output.SessionData
+			for _, session := range sessions {
+				stats := sessionStats[sessionKey(session.Vars)]
+				windows, _ := strconv.Atoi(session.Vars["session_windows"])
+				attached, _ := strconv.Atoi(session.Vars["session_attached"])
+				data = append(data, output.SessionData{
+					ServerSocket: session.Vars[tmux.VarServerSocket],
+					SessionName:  session.Vars["session_name"],
+					Windows:      windows,
+					Attached:     attached > 0,
+					Idle:         stats.IdleCount,
+					Running:      stats.RunningCount,
+					Waiting:      stats.WaitingCount,
+				})
+			}
+			return output.WriteSessions(os.Stdout, outputFormat, data)
+		}
+
 		// Output formatted sessions
 		for _, session := range sessions {
-			sessionName := session.Vars["session_name"]
-			stats := sessionStats[sessionName]
+			stats := sessionStats[sessionKey(session.Vars)]
 
 			line := output.ExpandSessionFormat(format, stats)
-			// Handle conditional: #{?session_attached, (attached),}
-			line = expandConditional(line, session.Vars)
 			// Trim trailing whitespace
 			line = strings.TrimRight(line, " ")
 			fmt.Println(line)
@@ -112,31 +128,15 @@ var lsCmd = &cobra.Command{
 	},
 }
 
+// sessionKey returns the key of the session of the tmux variables,
+// since sessions of different servers may have the same name.
+func sessionKey(vars map[string]string) string {
+	return vars[tmux.VarServerSocket] + ":" + vars["session_name"]
+}
+
 func init() {
 	lsCmd.Flags().StringVarP(&lsFormat, "format", "F", "", "Specify output format (tmux-compatible with tcmux extensions)")
+	addOutputFlag(lsCmd)
+	addFilterFlags(lsCmd)
 	rootCmd.AddCommand(lsCmd)
 }
-
-// expandConditional expands simple tmux conditionals like #{?var,true,false}
-func expandConditional(format string, vars map[string]string) string {
-	// Simple conditional pattern: #{?var,true_value,false_value}
-	re := regexp.MustCompile(`#\{\?([^,]+),([^,]*),([^}]*)\}`)
-
-	return re.ReplaceAllStringFunc(format, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) != 4 {
-			return match
-		}
-
-		varName := parts[1]
-		trueVal := parts[2]
-		falseVal := parts[3]
-
-		// Check if variable is truthy
-		val, ok := vars[varName]
-		if ok && val != "" && val != "0" {
-			return trueVal
-		}
-		return falseVal
-	})
-}
@@ -1,116 +1,147 @@
 package cmd
 
 import (
+	"cmp"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
-	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/mux"
 	"github.com/k1LoW/tcmux/output"
 	"github.com/k1LoW/tcmux/tmux"
 	"github.com/spf13/cobra"
 )
 
-const defaultWindowFormat = "#{window_index}: #{window_name} (#{window_panes} panes) #{agent_status}"
+const (
+	defaultWindowFormat = "#{window_index}: #{window_name} (#{window_panes} panes) #{agent_status}"
+	// defaultPaneFormat is the default format for multiplexers that do not report the windows of panes (e.g., Zellij).
+	defaultPaneFormat = "#{session_name}: #{pane_id} #{agent_status}"
+)
 
 var (
-	allWindows  bool
-	allSessions bool
-	target      string
-	lswFormat   string
+	allWindows   bool
+	allSessions  bool
+	target       string
+	lswFormat    string
+	lswFilter    string
+	lswSortOrder string
+	lswSort      string
+	lswReverse   bool
 )
 
 var lswCmd = &cobra.Command{
 	Use:     "list-windows",
 	Aliases: []string{"lsw"},
 	Short:   "List coding agent instances running in tmux windows",
-	Long:    `List coding agent instances (Claude Code, Copilot CLI, and Codex CLI) running in tmux windows with their status.`,
+	Long:    `List coding agent instances (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode) running in tmux windows with their status.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := output.ValidateStructuredFormat(outputFormat); err != nil {
+			return err
+		}
+		if err := output.ValidateSortOrder(lswSortOrder); err != nil {
+			return err
+		}
+		if err := output.ValidateAgentSortOrder(lswSort); err != nil {
+			return err
+		}
+		sortOrder := cmp.Or(lswSort, lswSortOrder)
+		filter, err := agentFilter()
+		if err != nil {
+			return err
+		}
+
 		// Use format string if specified, otherwise use default
 		format := lswFormat
 		if format == "" {
 			format = defaultWindowFormat
+			if !usingTmux() {
+				format = defaultPaneFormat
+			}
 		}
 
-		// Extract tmux variables from format
-		userVars := output.ExtractTmuxVars(format)
+		// Extract tmux variables from format, filter and sort order
+		userVars := mergeVars(output.ExtractTmuxVars(format), output.ExtractTmuxVars(lswFilter))
+		userVars = mergeVars(userVars, output.SortVars(sortOrder))
 
 		// Build combined variable list (user vars + internal vars)
-		allVars := mergeVars(userVars, tmux.InternalPaneVars)
-
-		// Build tmux format string
-		tmuxFormat := buildTmuxFormat(allVars)
+		allVars := mergeVars(userVars, mux.InternalPaneVars)
 
-		opts := tmux.ListPanesOptions{
+		opts := mux.ListOptions{
 			AllSessions: allSessions,
 			Target:      target,
 		}
 
 		ctx := cmd.Context()
-		panes, err := tmux.ListPanes(ctx, tmuxFormat, allVars, opts)
+		defer connectTmux(ctx)()
+		panes, err := multiplexer.ListPanes(ctx, allVars, opts)
 		if err != nil {
-			return fmt.Errorf("failed to list tmux panes: %w", err)
+			return fmt.Errorf("failed to list %s panes: %w", multiplexer.Name(), err)
 		}
 
+		panes = slices.DeleteFunc(panes, func(pane mux.Pane) bool {
+			return !filter.MatchSession(pane.Vars["session_name"])
+		})
+		paneAgents := newPaneDetector().inspectAll(ctx, panes, nil)
+
 		// Group panes by window
-		type windowData struct {
-			tmuxVars       map[string]string
-			agentInstances []output.AgentInfo
-		}
-		var windowOrder []string
-		windows := make(map[string]*windowData)
+		var windowOrder []*output.FormatContext
+		windows := make(map[string]*output.FormatContext)
 
-		for _, pane := range panes {
-			// Create window key
-			windowKey := fmt.Sprintf("%s:%s", pane.Vars["session_name"], pane.Vars["window_index"])
+		for i, pane := range panes {
+			// Create window key (sessions of different servers may have the same name)
+			windowKey := fmt.Sprintf("%s:%s:%s", pane.Vars[tmux.VarServerSocket], pane.Vars["session_name"], pane.Vars["window_index"])
 
 			// Initialize window data if not seen
-			if _, ok := windows[windowKey]; !ok {
-				windows[windowKey] = &windowData{
-					tmuxVars: pane.Vars,
+			win, ok := windows[windowKey]
+			if !ok {
+				win = &output.FormatContext{
+					TmuxVars: pane.Vars,
 				}
-				windowOrder = append(windowOrder, windowKey)
+				windows[windowKey] = win
+				windowOrder = append(windowOrder, win)
 			}
 
 			// Check if this is a coding agent pane
-			title := pane.Vars["pane_title"]
-			currentCommand := pane.Vars["pane_current_command"]
-
-			if detectedAgent := agent.Detect(title, currentCommand); detectedAgent != nil {
-				// Get coding agent status
-				paneID := pane.Vars["pane_id"]
-				content, err := tmux.CapturePane(ctx, paneID)
-				if err == nil {
-					status := detectedAgent.ParseStatus(content)
-					if status.State != agent.StateUnknown {
-						summary := detectedAgent.ExtractSummary(title)
-						windows[windowKey].agentInstances = append(windows[windowKey].agentInstances, output.AgentInfo{
-							AgentType: detectedAgent.Type(),
-							Icon:      detectedAgent.Icon(),
-							Summary:   summary,
-							Status:    status,
-						})
-					}
+			if pa := paneAgents[i]; pa != nil {
+				if inst := pa.agentInfo(pane.Vars["pane_id"]); filter.Match(inst, pane.Vars["session_name"]) {
+					win.AgentInstances = append(win.AgentInstances, inst)
 				}
 			}
 		}
 
-		// Build results
-		var results []string
-		for _, windowKey := range windowOrder {
-			win := windows[windowKey]
-
-			// Skip non-agent windows unless -A is specified
-			if !allWindows && len(win.agentInstances) == 0 {
-				continue
+		// Skip non-agent windows unless -A is specified, and windows not matching -f
+		windowOrder = slices.DeleteFunc(windowOrder, func(win *output.FormatContext) bool {
+			if !allWindows && len(win.AgentInstances) == 0 {
+				return true
 			}
-
-			// Expand format
-			ctx := &output.FormatContext{
-				TmuxVars:       win.tmuxVars,
-				AgentInstances: win.agentInstances,
+			return lswFilter != "" && !output.MatchFormat(lswFilter, win)
+		})
+		output.SortWindows(windowOrder, sortOrder, lswReverse)
+
+		if outputFormat != "" {
+			var data []output.WindowData
+			for _, win := range windowOrder {
+				agents := make([]output.AgentData, 0, len(win.AgentInstances))
+				for _, inst := range win.AgentInstances {
+					agents = append(agents, output.NewAgentData(inst))
+				}
+				data = append(data, output.WindowData{
+					ServerSocket: win.TmuxVars[tmux.VarServerSocket],
+					SessionName:  win.TmuxVars["session_name"],
+					WindowIndex:  win.TmuxVars["window_index"],
+					WindowName:   win.TmuxVars["window_name"],
+					Agents:       agents,
+				})
 			}
+			return output.WriteWindows(os.Stdout, outputFormat, data)
+		}
 
-			line := output.ExpandFormat(format, ctx)
+		// Build results
+		var results []string
+		for _, win := range windowOrder {
+			// Expand format
+			line := output.ExpandFormat(format, win)
 			// Trim trailing whitespace (in case agent_status is empty)
 			line = strings.TrimRight(line, " ")
 			results = append(results, line)
@@ -138,6 +169,13 @@ func init() {
 	lswCmd.Flags().BoolVarP(&allSessions, "all-sessions", "a", false, "List windows from all sessions")
 	lswCmd.Flags().StringVarP(&target, "target-session", "t", "", "Specify target session")
 	lswCmd.Flags().StringVarP(&lswFormat, "format", "F", "", "Specify output format (tmux-compatible with tcmux extensions)")
+	lswCmd.Flags().StringVarP(&lswFilter, "filter", "f", "", "Only show windows for which the filter format is true (tmux-compatible with tcmux extensions)")
+	lswCmd.Flags().StringVarP(&lswSortOrder, "sort-order", "O", "", "Sort windows: index, name, or time")
+	lswCmd.Flags().StringVar(&lswSort, "sort", "", "Sort windows by coding agents: state (Waiting, Idle, then Running), elapsed, session, agent, or waiting-age")
+	lswCmd.MarkFlagsMutuallyExclusive("sort-order", "sort")
+	lswCmd.Flags().BoolVarP(&lswReverse, "reverse", "r", false, "Reverse the sort order")
+	addOutputFlag(lswCmd)
+	addFilterFlags(lswCmd)
 	rootCmd.AddCommand(lswCmd)
 }
 
@@ -162,12 +200,3 @@ func mergeVars(vars1, vars2 []string) []string {
 
 	return result
 }
-
-// buildTmuxFormat builds a tmux -F format string from variable names.
-func buildTmuxFormat(vars []string) string {
-	var parts []string
-	for _, v := range vars {
-		parts = append(parts, fmt.Sprintf("#{%s}", v))
-	}
-	return strings.Join(parts, "\t")
-}
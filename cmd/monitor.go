@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/k1LoW/tcmux/event"
+	"github.com/k1LoW/tcmux/mux"
+	"github.com/k1LoW/tcmux/tmux"
+)
+
+const (
+	// watchDebounce is the debounce interval of changes in tmux.
+	watchDebounce = 300 * time.Millisecond
+	// rescanInterval is the interval of full scans while changes in tmux are watched,
+	// to catch state changes without pane output (e.g., reported only by hooks).
+	rescanInterval = 30 * time.Second
+)
+
+// agentMonitor keeps track of the coding agents in all panes.
+// On changes in tmux, only the panes whose output or title changed (and new panes) are inspected again.
+type agentMonitor struct {
+	mu     sync.Mutex
+	known  map[string]bool       // Panes inspected at least once
+	agents map[string]*paneAgent // Detected agents by pane key (see paneKey)
+}
+
+func newAgentMonitor() *agentMonitor {
+	return &agentMonitor{
+		known:  make(map[string]bool),
+		agents: make(map[string]*paneAgent),
+	}
+}
+
+// scan inspects all panes.
+func (m *agentMonitor) scan(ctx context.Context) ([]agentPane, error) {
+	return m.inspect(ctx, nil)
+}
+
+// update inspects the panes changed in tmux and new panes, and returns all agents.
+func (m *agentMonitor) update(ctx context.Context, changes []tmux.Change) ([]agentPane, error) {
+	changed := make(map[string]bool)
+	for _, c := range changes {
+		if c.PaneID != "" {
+			changed[tmux.PaneKey(c.Socket, c.PaneID)] = true
+		}
+	}
+	return m.inspect(ctx, changed)
+}
+
+// inspect inspects the changed panes (all panes if changed is nil) and the panes not inspected yet.
+// The agents of the other panes are kept, with their current tmux variables.
+func (m *agentMonitor) inspect(ctx context.Context, changed map[string]bool) ([]agentPane, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	panes, err := listAllPanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var targets []mux.Pane
+	for _, pane := range panes {
+		id := paneKey(pane.Vars)
+		if changed == nil || changed[id] || !m.known[id] {
+			targets = append(targets, pane)
+		}
+	}
+	prev := func(pane mux.Pane) *paneAgent {
+		return m.agents[paneKey(pane.Vars)]
+	}
+	for i, pa := range newPaneDetector().inspectAll(ctx, targets, prev) {
+		id := paneKey(targets[i].Vars)
+		m.known[id] = true
+		if pa != nil {
+			m.agents[id] = pa
+		} else {
+			delete(m.agents, id)
+		}
+	}
+
+	// Forget closed panes
+	current := make(map[string]bool, len(panes))
+	for _, pane := range panes {
+		current[paneKey(pane.Vars)] = true
+	}
+	for id := range m.known {
+		if !current[id] {
+			delete(m.known, id)
+			delete(m.agents, id)
+		}
+	}
+
+	var agents []agentPane
+	for _, pane := range panes {
+		if pa, ok := m.agents[paneKey(pane.Vars)]; ok {
+			agents = append(agents, agentPane{paneAgent: pa, vars: pane.Vars})
+		}
+	}
+	return agents, nil
+}
+
+// monitorEvents monitors coding agents and calls handle with the changes since the previous scan,
+// until the context is canceled or handle returns an error.
+// If changes in tmux are watched in control mode (--watch-tmux), the panes are inspected again when they change
+// (with full scans every rescanInterval); otherwise all panes are scanned at the interval.
+// The first scan is the baseline; agents found in it are reported as appeared only if initial is true.
+// Scan errors (e.g., the tmux server is not running) are reported to errw and retried at the next interval.
+func monitorEvents(ctx context.Context, interval time.Duration, initial bool, errw io.Writer, handle func([]event.Event) error) error {
+	var (
+		prev    []event.Agent
+		first   = true
+		watcher *tmux.Watcher
+	)
+	monitor := newAgentMonitor()
+	report := func(agents []agentPane, err error) error {
+		if err != nil {
+			if ctx.Err() == nil {
+				_, _ = fmt.Fprintln(errw, err)
+			}
+			return nil
+		}
+		cur := eventAgents(agents)
+		if events := event.Diff(prev, cur, time.Now()); len(events) > 0 && (!first || initial) {
+			if err := handle(events); err != nil {
+				return err
+			}
+		}
+		prev = cur
+		first = false
+		return nil
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		var changes <-chan []tmux.Change
+		if watcher != nil {
+			changes = watcher.Changes()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := report(monitor.scan(ctx)); err != nil {
+				return err
+			}
+			if watcher == nil && watchingTmux() {
+				// Start (or restart) watching; fall back to polling if unavailable
+				watcher, _ = tmux.Watch(ctx, watchDebounce)
+			}
+			if watcher != nil {
+				timer.Reset(max(interval, rescanInterval))
+			} else {
+				timer.Reset(interval)
+			}
+		case batch, ok := <-changes:
+			if !ok {
+				// The watcher ended (e.g., the tmux server exited): poll until it can be started again
+				watcher = nil
+				timer.Reset(interval)
+				continue
+			}
+			if err := report(monitor.update(ctx, batch)); err != nil {
+				return err
+			}
+		}
+	}
+}
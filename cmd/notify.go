@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/tcmux/event"
+	"github.com/k1LoW/tcmux/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	notifyInterval time.Duration
+	notifyConfig   string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Notify of coding agent state transitions",
+	Long: `Watch coding agent instances (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode) in all tmux sessions and notify of state transitions.
+
+By default, it notifies when a task is finished (Running to Idle) and when an agent needs your input (any to Waiting) via tmux display-message and the terminal bell.
+Sinks and per-agent-type and per-session rules can be configured in notify.yaml ($XDG_CONFIG_HOME/tcmux/notify.yaml).
+
+Sinks:
+  notify-send  Desktop notification via notify-send
+  tmux         tmux display-message
+  bell         Terminal bell
+  osc9         OSC 9 desktop notification of the terminal
+  osc777       OSC 777 desktop notification of the terminal`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if notifyInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", notifyInterval)
+		}
+		config, err := notify.LoadConfig(notifyConfig)
+		if err != nil {
+			return err
+		}
+
+		// Terminal sinks write to the terminal of the daemon; pass OSC sequences through tmux to the outer terminal
+		passthrough := os.Getenv("TMUX") != ""
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		defer connectTmux(ctx)()
+
+		return monitorEvents(ctx, notifyInterval, false, cmd.ErrOrStderr(), func(events []event.Event) error {
+			for _, e := range events {
+				n := notify.Message(e)
+				for _, name := range config.Match(e) {
+					s, err := notify.NewSink(name, cmd.OutOrStdout(), passthrough)
+					if err != nil {
+						return err
+					}
+					if err := s.Notify(ctx, n); err != nil {
+						// Keep running even if a sink is not available
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to notify via %s: %v\n", name, err)
+					}
+				}
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	notifyCmd.Flags().DurationVarP(&notifyInterval, "interval", "n", 2*time.Second, "Polling interval if changes in tmux are not watched")
+	addWatchTmuxFlag(notifyCmd)
+	notifyCmd.Flags().StringVar(&notifyConfig, "config", notify.DefaultConfigPath(), "Path to the notification config file")
+	rootCmd.AddCommand(notifyCmd)
+}
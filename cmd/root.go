@@ -1,25 +1,77 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/mux"
 	"github.com/k1LoW/tcmux/output"
+	"github.com/k1LoW/tcmux/tmux"
+	"github.com/k1LoW/tcmux/zellij"
 	"github.com/spf13/cobra"
 )
 
-var colorMode string
+var (
+	colorMode    string
+	scanContent  bool
+	outputFormat string // Structured output format of listing commands (empty means text)
+
+	// Terminal multiplexer to inspect
+	multiplexerName string
+	multiplexer     mux.Multiplexer = tmux.Backend{}
+
+	// tmux servers to query
+	socketName string
+	socketPath string
+	allServers bool
+
+	// Run tmux commands over one control-mode connection
+	controlMode bool
+
+	// Watch changes in tmux in control mode (monitoring commands)
+	watchTmux bool
+
+	// Agent filters of listing commands
+	filterStates       []string
+	filterAgents       []string
+	filterModes        []string
+	filterSessions     []string
+	filterSummaryMatch string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "tcmux",
 	Short: "terminal and coding agent mux viewer",
-	Long:  `tcmux is a terminal and coding agent mux viewer (supports Claude Code, Copilot CLI, and Codex CLI).`,
+	Long:  `tcmux is a terminal and coding agent mux viewer (supports Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode).`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return output.SetColorMode(colorMode)
+		if err := output.SetColorMode(colorMode); err != nil {
+			return err
+		}
+		if err := setMultiplexer(); err != nil {
+			return err
+		}
+		if err := setTmuxServers(); err != nil {
+			return err
+		}
+		if !detectsAgents(cmd) {
+			return nil
+		}
+		return loadCustomAgents()
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "When to use colors: always, never, or auto")
+	rootCmd.PersistentFlags().BoolVar(&scanContent, "scan-content", false, "Also capture panes without title or process signals and detect agents by their on-screen chrome")
+	rootCmd.PersistentFlags().StringVar(&multiplexerName, "multiplexer", "auto", "Terminal multiplexer to inspect: tmux, zellij, or auto (zellij inside Zellij, tmux otherwise)")
+	rootCmd.PersistentFlags().StringVarP(&socketName, "socket-name", "L", "", "Use the tmux server with the socket name (like tmux -L)")
+	rootCmd.PersistentFlags().StringVarP(&socketPath, "socket-path", "S", "", "Use the tmux server with the socket path (like tmux -S)")
+	rootCmd.PersistentFlags().BoolVar(&allServers, "all-servers", false, "Aggregate all tmux servers with sockets in $TMUX_TMPDIR/tmux-$UID")
+	rootCmd.PersistentFlags().BoolVar(&controlMode, "control-mode", false, "Run tmux commands over one control-mode connection instead of a process per command (attaches a read-only client to a session)")
+	rootCmd.MarkFlagsMutuallyExclusive("socket-name", "socket-path", "all-servers")
 }
 
 func Execute() {
@@ -27,3 +79,129 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// addOutputFlag adds the structured output flag to the listing command.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output structured data instead of text: json, ndjson, or yaml")
+}
+
+// addFilterFlags adds the coding agent filter flags to the listing command.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&filterStates, "state", nil, "Only include agents in the states: Idle, Running, or Waiting (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&filterAgents, "agent", nil, "Only include agents of the types, e.g. claude,codex (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&filterModes, "mode", nil, "Only include agents in the modes, e.g. plan (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&filterSessions, "session", nil, "Only include sessions whose names match the glob patterns (comma-separated or repeated)")
+	cmd.Flags().StringVar(&filterSummaryMatch, "summary-match", "", "Only include agents whose summaries match the regular expression")
+}
+
+// addWatchTmuxFlag adds the flag to watch changes in tmux to the monitoring command.
+func addWatchTmuxFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&watchTmux, "watch-tmux", false, "Watch changes in tmux in control mode and inspect only the changed panes (attaches a read-only client to each session)")
+}
+
+// agentFilter builds the coding agent filter from the filter flags.
+func agentFilter() (*output.AgentFilter, error) {
+	return output.NewAgentFilter(filterStates, filterAgents, filterModes, filterSessions, filterSummaryMatch)
+}
+
+// connectTmux connects to tmux in control mode (--control-mode), so that the command runs tmux commands
+// over one connection. Returns the function to disconnect. If control mode is disabled or unavailable,
+// tmux commands are run as processes. Does nothing with the other multiplexers.
+func connectTmux(ctx context.Context) func() {
+	if !usingTmux() || !controlMode {
+		return func() {}
+	}
+	_ = tmux.Connect(ctx)
+	return tmux.Disconnect
+}
+
+// setMultiplexer sets the terminal multiplexer to inspect from the --multiplexer flag.
+func setMultiplexer() error {
+	name := multiplexerName
+	if name == "auto" {
+		name = "tmux"
+		if os.Getenv("ZELLIJ") != "" && os.Getenv("TMUX") == "" {
+			name = "zellij"
+		}
+	}
+	switch name {
+	case "tmux":
+		multiplexer = tmux.Backend{}
+	case "zellij":
+		multiplexer = zellij.Backend{}
+	default:
+		return fmt.Errorf("invalid multiplexer: %s (must be tmux, zellij, or auto)", multiplexerName)
+	}
+	return nil
+}
+
+// usingTmux reports whether the multiplexer to inspect is tmux.
+func usingTmux() bool {
+	_, ok := multiplexer.(tmux.Backend)
+	return ok
+}
+
+// watchingTmux reports whether changes in tmux are watched (--watch-tmux with tmux).
+// Only tmux notifies changes; the other multiplexers are polled.
+func watchingTmux() bool {
+	return watchTmux && usingTmux()
+}
+
+// setTmuxServers sets the tmux servers to query from the -L, -S and --all-servers flags.
+// Without them, the server of the current tmux client (or the default server) is used.
+func setTmuxServers() error {
+	if !usingTmux() && (socketName != "" || socketPath != "" || allServers) {
+		return fmt.Errorf("-L, -S and --all-servers are only available for tmux")
+	}
+	switch {
+	case socketName != "":
+		tmux.SetSockets([]string{tmux.SocketPath(socketName)})
+	case socketPath != "":
+		path, err := filepath.Abs(socketPath)
+		if err != nil {
+			return err
+		}
+		tmux.SetSockets([]string{path})
+	case allServers:
+		paths, err := tmux.DiscoverSockets()
+		if err != nil {
+			return fmt.Errorf("failed to discover tmux servers: %w", err)
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no tmux servers found in %s", tmux.SocketDir())
+		}
+		tmux.SetSockets(paths)
+	}
+	return nil
+}
+
+// annotationNoDetection is the annotation of commands that do not detect coding agents
+// (e.g., hooks run in the agents' own pipelines), so that a broken agents.yaml does not break them.
+const annotationNoDetection = "no-detection"
+
+// detectsAgents reports whether the command (or its parent) detects coding agents.
+func detectsAgents(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[annotationNoDetection]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// loadCustomAgents registers user-defined agents from agents.yaml.
+func loadCustomAgents() error {
+	customAgents, err := agent.LoadConfig(agent.DefaultConfigPath())
+	if err != nil {
+		return err
+	}
+	var ds []agent.Detector
+	for _, a := range customAgents {
+		if a.Color() != "" {
+			output.SetThemeColor(a.Type(), a.Color())
+		}
+		ds = append(ds, a)
+	}
+	agent.Register(ds...)
+	return nil
+}
@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/k1LoW/tcmux/agent"
 	"github.com/k1LoW/tcmux/output"
-	"github.com/k1LoW/tcmux/tmux"
 	"github.com/spf13/cobra"
 )
 
@@ -16,36 +16,31 @@ var statsFormat string
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show total coding agent stats across all sessions",
-	Long:  `Show aggregated coding agent statistics (Claude Code, Copilot CLI, and Codex CLI) across all tmux sessions.`,
+	Long:  `Show aggregated coding agent statistics (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode) across all tmux sessions.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := output.ValidateStructuredFormat(outputFormat); err != nil {
+			return err
+		}
+		filter, err := agentFilter()
+		if err != nil {
+			return err
+		}
+
 		ctx := cmd.Context()
+		defer connectTmux(ctx)()
 
-		// Get all panes
-		paneFormat := buildTmuxFormat(tmux.InternalPaneVars)
-		panes, err := tmux.ListPanes(ctx, paneFormat, tmux.InternalPaneVars, tmux.ListPanesOptions{AllSessions: true})
+		agents, err := scanAgents(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to list tmux panes: %w", err)
+			return err
 		}
 
 		// Count agent states
 		var totalStats output.TotalStatsContext
-		for _, pane := range panes {
-			detectedAgent := agent.Detect(pane.Vars["pane_title"], pane.Vars["pane_current_command"])
-			if detectedAgent == nil {
-				continue
-			}
-
-			content, err := tmux.CapturePane(ctx, pane.Vars["pane_id"])
-			if err != nil {
+		for _, pa := range agents {
+			if !filter.Match(pa.agentInfo(pa.vars["pane_id"]), pa.vars["session_name"]) {
 				continue
 			}
-
-			status := detectedAgent.ParseStatus(content)
-			if status.State == agent.StateUnknown {
-				continue
-			}
-
-			switch status.State {
+			switch pa.status.State {
 			case agent.StateIdle:
 				totalStats.IdleCount++
 			case agent.StateRunning:
@@ -55,6 +50,10 @@ var statsCmd = &cobra.Command{
 			}
 		}
 
+		if outputFormat != "" {
+			return output.WriteStats(os.Stdout, outputFormat, output.NewStatsData(&totalStats))
+		}
+
 		// Output
 		format := statsFormat
 		if format == "" {
@@ -70,5 +69,7 @@ var statsCmd = &cobra.Command{
 
 func init() {
 	statsCmd.Flags().StringVarP(&statsFormat, "format", "F", "", "Specify output format (use #{total_idle}, #{total_running}, #{total_waiting}, #{total_agents}, #{agent_status})")
+	addOutputFlag(statsCmd)
+	addFilterFlags(statsCmd)
 	rootCmd.AddCommand(statsCmd)
 }
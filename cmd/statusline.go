@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/hook"
+	"github.com/k1LoW/tcmux/output"
+	"github.com/spf13/cobra"
+)
+
+const defaultStatusLineFormat = "#{model} $#{cost_usd} #{cwd}"
+
+var statusLineFormat string
+
+var claudeStatusLineCmd = &cobra.Command{
+	Use:   "claude-statusline",
+	Short: "Render Claude Code's status line and record its session info",
+	Long: `Render Claude Code's status line from the JSON read from stdin.
+
+The session info (model, cost, session ID, etc.) is also recorded for the current tmux pane ($TMUX_PANE),
+so that list-windows can show it with #{agent_model}, #{agent_cost_usd} and #{agent_session_id}.
+
+Set this command as statusLine in Claude Code's settings.json.
+
+Format variables:
+  #{model}          Model display name (e.g., Opus)
+  #{model_id}       Model ID
+  #{cwd}            Current working directory
+  #{session_id}     Session ID
+  #{version}        Claude Code version
+  #{cost_usd}       Total cost in USD
+  #{duration}       Total duration of the session
+  #{lines_added}    Total lines added
+  #{lines_removed}  Total lines removed`,
+	Args: cobra.NoArgs,
+	// Run by Claude Code's status line, which must not fail because of agents.yaml
+	Annotations: map[string]string{annotationNoDetection: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		payload, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read statusLine payload: %w", err)
+		}
+
+		a := &agent.ClaudeAgent{}
+		info, err := a.ParseStatusLine(payload, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if paneID := currentPaneKey(); paneID != "" {
+			// Saving is best-effort: the status line is printed even if the state directory is not writable
+			if err := hook.Default().SaveSession(agent.TypeClaude, paneID, info); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to save session info: %v\n", err)
+			}
+		}
+
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), output.ExpandStatusLineFormat(statusLineFormat, info))
+		return err
+	},
+}
+
+func init() {
+	claudeStatusLineCmd.Flags().StringVarP(&statusLineFormat, "format", "F", defaultStatusLineFormat, "Format string for the status line")
+	rootCmd.AddCommand(claudeStatusLineCmd)
+}
@@ -0,0 +1,421 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/mux"
+	"github.com/k1LoW/tcmux/output"
+	"github.com/k1LoW/tcmux/tmux"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var watchInterval time.Duration
+
+// watchFilters are the state filters cycled by the filter key ("" shows all states).
+var watchFilters = []string{"", agent.StateWaiting, agent.StateRunning, agent.StateIdle}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch coding agent instances in a live dashboard",
+	Long: `Watch coding agent instances (Claude Code, Copilot CLI, Codex CLI, Gemini CLI, Aider, and OpenCode) in all tmux sessions in a full-screen live dashboard.
+
+Keys:
+  ↑/k, ↓/j  Move the cursor
+  enter     Jump to the pane of the agent
+  p         Toggle the preview of the pane
+  f         Cycle the state filter (All, Waiting, Running, Idle)
+  r         Refresh now
+  q         Quit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchInterval <= 0 {
+			return fmt.Errorf("invalid interval: %s", watchInterval)
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		defer connectTmux(ctx)()
+		m := &watchModel{
+			ctx:      ctx,
+			interval: watchInterval,
+			monitor:  newAgentMonitor(),
+		}
+		_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+		return err
+	},
+}
+
+func init() {
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "n", 2*time.Second, "Refresh interval, and scan interval if changes in tmux are not watched")
+	addWatchTmuxFlag(watchCmd)
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchScanMsg is the result of a pane scan.
+type watchScanMsg struct {
+	agents []agentPane
+	err    error
+	manual bool // Requested by the refresh key, outside the chain of periodic scans
+}
+
+// watchUpdateMsg is the result of inspecting the panes changed in tmux.
+type watchUpdateMsg struct {
+	agents []agentPane
+	err    error
+}
+
+// watchTickMsg triggers a periodic pane scan.
+type watchTickMsg struct{}
+
+// watchStartedMsg is the result of starting to watch changes in tmux.
+type watchStartedMsg struct {
+	watcher *tmux.Watcher // nil if changes cannot be watched
+}
+
+// watchChangesMsg is a batch of changes in tmux (ok is false if watching ended).
+type watchChangesMsg struct {
+	changes []tmux.Change
+	ok      bool
+}
+
+// watchPreviewMsg is the captured content of the selected pane.
+type watchPreviewMsg struct {
+	paneID  string
+	content string
+	err     error
+}
+
+// watchJumpMsg is the result of jumping to a pane.
+type watchJumpMsg struct {
+	err error
+}
+
+// watchModel is the bubbletea model of tcmux watch.
+type watchModel struct {
+	ctx      context.Context
+	interval time.Duration
+	monitor  *agentMonitor
+	watcher  *tmux.Watcher // nil if changes in tmux are not watched (polling)
+	watching bool          // Watching changes in tmux has been started
+
+	agents    []agentPane
+	err       error
+	updatedAt time.Time
+	scannedAt time.Time // Time of the last full scan
+
+	cursor      int
+	filter      int  // Index of watchFilters
+	showPreview bool // Show the content of the selected pane
+	preview     watchPreviewMsg
+	message     string // Result of the last action
+
+	width  int
+	height int
+}
+
+func (m *watchModel) Init() tea.Cmd {
+	if !watchingTmux() {
+		return m.scan(false)
+	}
+	return tea.Batch(m.scan(false), m.watch())
+}
+
+func (m *watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case watchScanMsg:
+		m.agents = msg.agents
+		m.err = msg.err
+		m.updatedAt = time.Now()
+		m.scannedAt = m.updatedAt
+		m.clampCursor()
+		if msg.manual {
+			// The next periodic scan is already scheduled
+			return m, m.capturePreview()
+		}
+		return m, tea.Batch(m.capturePreview(), m.tick())
+	case watchUpdateMsg:
+		m.agents = msg.agents
+		m.err = msg.err
+		m.updatedAt = time.Now()
+		m.clampCursor()
+		return m, m.capturePreview()
+	case watchTickMsg:
+		// While changes are watched, scan all panes only every rescanInterval and just refresh otherwise
+		if m.watcher == nil || time.Since(m.scannedAt) >= rescanInterval {
+			return m, m.scan(false)
+		}
+		return m, tea.Batch(m.capturePreview(), m.tick())
+	case watchStartedMsg:
+		m.watcher = msg.watcher
+		m.watching = msg.watcher != nil
+		return m, m.waitChanges()
+	case watchChangesMsg:
+		if !msg.ok {
+			// Watching ended (e.g., the tmux server exited): fall back to polling and try again at the next scan
+			m.watcher = nil
+			m.watching = false
+			return m, nil
+		}
+		return m, tea.Batch(m.update(msg.changes), m.waitChanges())
+	case watchPreviewMsg:
+		m.preview = msg
+	case watchJumpMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+		} else {
+			m.message = ""
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, m.capturePreview()
+		case "down", "j":
+			if m.cursor < len(m.visible())-1 {
+				m.cursor++
+			}
+			return m, m.capturePreview()
+		case "enter":
+			return m, m.jump()
+		case "p":
+			m.showPreview = !m.showPreview
+			return m, m.capturePreview()
+		case "f":
+			m.filter = (m.filter + 1) % len(watchFilters)
+			m.clampCursor()
+			return m, m.capturePreview()
+		case "r":
+			return m, m.scan(true)
+		}
+	}
+	return m, nil
+}
+
+func (m *watchModel) View() string {
+	var b strings.Builder
+
+	filter := watchFilters[m.filter]
+	if filter == "" {
+		filter = "All"
+	}
+	visible := m.visible()
+	fmt.Fprintf(&b, "tcmux watch  filter: %s  agents: %d/%d  updated: %s\n\n", filter, len(visible), len(m.agents), m.updatedAt.Format("15:04:05"))
+
+	if m.err != nil {
+		fmt.Fprintf(&b, "%s\n", m.err)
+	}
+
+	// Table
+	headers := []string{"SESSION", "WINDOW", "AGENT", "SUMMARY", "STATE", "MODE", "ELAPSED"}
+	rows := make([][]string, 0, len(visible))
+	for _, a := range visible {
+		elapsed := ""
+		if !a.since.IsZero() {
+			elapsed = agent.FormatDuration(time.Since(a.since))
+		}
+		rows = append(rows, []string{
+			a.vars["session_name"],
+			a.vars["window_index"] + ":" + a.vars["window_name"],
+			a.detector.Icon() + " " + string(a.detector.Type()),
+			a.summary,
+			a.status.State,
+			a.status.Mode,
+			elapsed,
+		})
+	}
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = runewidth.StringWidth(h)
+	}
+	for _, row := range rows {
+		for i, col := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(col))
+		}
+	}
+	// Truncate the summary to fit the terminal width
+	if m.width > 0 {
+		total := 2 + len(widths)*2
+		for i, w := range widths {
+			if i != 3 {
+				total += w
+			}
+		}
+		widths[3] = max(min(widths[3], m.width-total), runewidth.StringWidth(headers[3]))
+	}
+
+	b.WriteString("  " + m.formatRow(headers, widths, nil) + "\n")
+	if len(rows) == 0 {
+		b.WriteString("  No coding agent instances found.\n")
+	}
+	for i, row := range rows {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		b.WriteString(cursor + m.formatRow(row, widths, visible[i].paneAgent) + "\n")
+	}
+
+	// Preview of the selected pane
+	if m.showPreview && len(visible) > 0 {
+		fmt.Fprintf(&b, "\n── %s ──\n", m.preview.paneID)
+		lines := strings.Split(strings.TrimRight(m.preview.content, "\n"), "\n")
+		if m.preview.err != nil {
+			lines = []string{m.preview.err.Error()}
+		}
+		// Leave room for the header, table, footer and separators
+		if room := m.height - len(rows) - 8; room > 0 && len(lines) > room {
+			lines = lines[len(lines)-room:]
+		}
+		for _, line := range lines {
+			if m.width > 0 {
+				line = runewidth.Truncate(line, m.width, "")
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	b.WriteString("↑/k ↓/j move • enter jump • p preview • f filter • r refresh • q quit")
+
+	return b.String()
+}
+
+// formatRow pads the columns to the widths and colors the state and mode of the agent.
+func (m *watchModel) formatRow(cols []string, widths []int, pa *paneAgent) string {
+	parts := make([]string, len(cols))
+	for i, col := range cols {
+		col = runewidth.FillRight(runewidth.Truncate(col, widths[i], "…"), widths[i])
+		if pa != nil {
+			switch i {
+			case 2:
+				col = output.ColorTheme(pa.detector.Type(), col)
+			case 4:
+				col = output.ColorState(pa.status.State, col)
+			case 5:
+				col = output.ColorMode(col)
+			}
+		}
+		parts[i] = col
+	}
+	return strings.TrimRight(strings.Join(parts, "  "), " ")
+}
+
+// visible returns the agents that match the state filter.
+func (m *watchModel) visible() []agentPane {
+	filter := watchFilters[m.filter]
+	if filter == "" {
+		return m.agents
+	}
+	var agents []agentPane
+	for _, a := range m.agents {
+		if a.status.State == filter {
+			agents = append(agents, a)
+		}
+	}
+	return agents
+}
+
+// selected returns the agent under the cursor, or nil if there is none.
+func (m *watchModel) selected() *agentPane {
+	visible := m.visible()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return nil
+	}
+	return &visible[m.cursor]
+}
+
+// clampCursor keeps the cursor within the visible agents.
+func (m *watchModel) clampCursor() {
+	m.cursor = max(min(m.cursor, len(m.visible())-1), 0)
+}
+
+// scan detects coding agents in all panes. A manual scan does not schedule the next periodic scan.
+func (m *watchModel) scan(manual bool) tea.Cmd {
+	cmds := []tea.Cmd{func() tea.Msg {
+		agents, err := m.monitor.scan(m.ctx)
+		return watchScanMsg{agents: agents, err: err, manual: manual}
+	}}
+	if watchingTmux() && !m.watching && !m.scannedAt.IsZero() {
+		cmds = append(cmds, m.watch())
+	}
+	return tea.Batch(cmds...)
+}
+
+// update inspects the panes changed in tmux.
+func (m *watchModel) update(changes []tmux.Change) tea.Cmd {
+	return func() tea.Msg {
+		agents, err := m.monitor.update(m.ctx, changes)
+		return watchUpdateMsg{agents: agents, err: err}
+	}
+}
+
+// tick schedules the next periodic scan.
+func (m *watchModel) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// watch starts watching changes in tmux.
+func (m *watchModel) watch() tea.Cmd {
+	m.watching = true
+	return func() tea.Msg {
+		w, err := tmux.Watch(m.ctx, watchDebounce)
+		if err != nil {
+			return watchStartedMsg{}
+		}
+		return watchStartedMsg{watcher: w}
+	}
+}
+
+// waitChanges waits for the next batch of changes in tmux.
+func (m *watchModel) waitChanges() tea.Cmd {
+	w := m.watcher
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		changes, ok := <-w.Changes()
+		return watchChangesMsg{changes: changes, ok: ok}
+	}
+}
+
+// capturePreview captures the content of the selected pane if the preview is shown.
+func (m *watchModel) capturePreview() tea.Cmd {
+	a := m.selected()
+	if !m.showPreview || a == nil {
+		return nil
+	}
+	pane := mux.Pane{Vars: a.vars}
+	return func() tea.Msg {
+		content, err := multiplexer.CapturePane(m.ctx, pane, mux.CaptureOptions{})
+		return watchPreviewMsg{paneID: pane.Vars["pane_id"], content: content, err: err}
+	}
+}
+
+// jump switches the current tmux client to the pane of the selected agent.
+func (m *watchModel) jump() tea.Cmd {
+	a := m.selected()
+	if a == nil {
+		return nil
+	}
+	pane := mux.Pane{Vars: a.vars}
+	return func() tea.Msg {
+		return watchJumpMsg{err: multiplexer.FocusPane(m.ctx, pane)}
+	}
+}
@@ -0,0 +1,95 @@
+package event
+
+import (
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+// Kind is the kind of change of a coding agent.
+type Kind string
+
+const (
+	KindAppeared    Kind = "appeared"    // A coding agent is detected in a pane
+	KindState       Kind = "state"       // The state of a coding agent changed
+	KindMode        Kind = "mode"        // The mode of a coding agent changed
+	KindSummary     Kind = "summary"     // The summary (pane title) of a coding agent changed
+	KindDisappeared Kind = "disappeared" // A coding agent is no longer detected in a pane
+)
+
+// Agent is a coding agent observed in a tmux pane.
+type Agent struct {
+	PaneID       string       `json:"pane_id"`
+	ServerSocket string       `json:"server_socket"` // Socket path of the tmux server of the pane
+	Session      string       `json:"session"`
+	WindowIndex  string       `json:"window_index"`
+	WindowName   string       `json:"window_name"`
+	Type         agent.Type   `json:"type"`
+	Icon         string       `json:"icon"`
+	Summary      string       `json:"summary"`
+	Status       agent.Status `json:"status"`
+	Since        time.Time    `json:"since"` // When the current state was first observed
+}
+
+// Event is a change of a coding agent between two observations.
+type Event struct {
+	Kind       Kind          `json:"kind"`
+	Time       time.Time     `json:"time"`
+	Agent      Agent         `json:"agent"`                 // The current agent (the last observed one for KindDisappeared)
+	Old        *agent.Status `json:"old"`                   // nil for KindAppeared
+	New        *agent.Status `json:"new"`                   // nil for KindDisappeared
+	OldSummary string        `json:"old_summary,omitempty"` // The previous summary for KindSummary
+}
+
+// paneKey identifies a pane across tmux servers.
+type paneKey struct {
+	serverSocket string
+	paneID       string
+}
+
+// paneKey returns the key of the pane of the agent.
+func (a Agent) paneKey() paneKey {
+	return paneKey{serverSocket: a.ServerSocket, paneID: a.PaneID}
+}
+
+// Diff returns the changes between the previous and current observations.
+// Agents are matched by tmux server and pane ID; a different agent type in the same pane is
+// reported as the old agent disappearing and the new one appearing.
+// Simultaneous changes of an agent are reported in the order state, mode and summary.
+func Diff(prev, cur []Agent, now time.Time) []Event {
+	prevByPane := make(map[paneKey]Agent, len(prev))
+	for _, a := range prev {
+		prevByPane[a.paneKey()] = a
+	}
+	curByPane := make(map[paneKey]Agent, len(cur))
+	for _, a := range cur {
+		curByPane[a.paneKey()] = a
+	}
+
+	var events []Event
+	for _, p := range prev {
+		if c, ok := curByPane[p.paneKey()]; !ok || c.Type != p.Type {
+			old := p.Status
+			events = append(events, Event{Kind: KindDisappeared, Time: now, Agent: p, Old: &old})
+		}
+	}
+	for _, c := range cur {
+		cs := c.Status
+		p, ok := prevByPane[c.paneKey()]
+		if !ok || p.Type != c.Type {
+			events = append(events, Event{Kind: KindAppeared, Time: now, Agent: c, New: &cs})
+			continue
+		}
+		ps := p.Status
+		if p.Status.State != c.Status.State {
+			events = append(events, Event{Kind: KindState, Time: now, Agent: c, Old: &ps, New: &cs})
+		}
+		if p.Status.Mode != c.Status.Mode {
+			events = append(events, Event{Kind: KindMode, Time: now, Agent: c, Old: &ps, New: &cs})
+		}
+		if p.Summary != c.Summary {
+			events = append(events, Event{Kind: KindSummary, Time: now, Agent: c, Old: &ps, New: &cs, OldSummary: p.Summary})
+		}
+	}
+	return events
+}
@@ -0,0 +1,125 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	claude := func(paneID, state string) Agent {
+		return Agent{PaneID: paneID, Type: agent.TypeClaude, Status: agent.Status{State: state}}
+	}
+	codex := func(paneID, state string) Agent {
+		return Agent{PaneID: paneID, Type: agent.TypeCodex, Status: agent.Status{State: state}}
+	}
+	tests := []struct {
+		name string
+		prev []Agent
+		cur  []Agent
+		want []Kind
+	}{
+		{
+			name: "No change",
+			prev: []Agent{claude("%1", agent.StateIdle)},
+			cur:  []Agent{claude("%1", agent.StateIdle)},
+			want: nil,
+		},
+		{
+			name: "Description change is not a state change",
+			prev: []Agent{claude("%1", agent.StateRunning)},
+			cur:  []Agent{{PaneID: "%1", Type: agent.TypeClaude, Status: agent.Status{State: agent.StateRunning, Description: "5s"}}},
+			want: nil,
+		},
+		{
+			name: "Appeared",
+			prev: nil,
+			cur:  []Agent{claude("%1", agent.StateIdle), codex("%2", agent.StateRunning)},
+			want: []Kind{KindAppeared, KindAppeared},
+		},
+		{
+			name: "State changed",
+			prev: []Agent{claude("%1", agent.StateRunning)},
+			cur:  []Agent{claude("%1", agent.StateIdle)},
+			want: []Kind{KindState},
+		},
+		{
+			name: "Mode changed",
+			prev: []Agent{claude("%1", agent.StateRunning)},
+			cur:  []Agent{{PaneID: "%1", Type: agent.TypeClaude, Status: agent.Status{State: agent.StateRunning, Mode: agent.ModePlan}}},
+			want: []Kind{KindMode},
+		},
+		{
+			name: "Summary changed",
+			prev: []Agent{claude("%1", agent.StateRunning)},
+			cur:  []Agent{{PaneID: "%1", Type: agent.TypeClaude, Summary: "Fix login bug", Status: agent.Status{State: agent.StateRunning}}},
+			want: []Kind{KindSummary},
+		},
+		{
+			name: "State, mode and summary changed",
+			prev: []Agent{claude("%1", agent.StateRunning)},
+			cur:  []Agent{{PaneID: "%1", Type: agent.TypeClaude, Summary: "Fix login bug", Status: agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan}}},
+			want: []Kind{KindState, KindMode, KindSummary},
+		},
+		{
+			name: "Same pane ID on another server",
+			prev: []Agent{claude("%1", agent.StateIdle)},
+			cur:  []Agent{claude("%1", agent.StateIdle), {PaneID: "%1", ServerSocket: "/tmp/tmux-1000/work", Type: agent.TypeClaude, Status: agent.Status{State: agent.StateRunning}}},
+			want: []Kind{KindAppeared},
+		},
+		{
+			name: "Disappeared",
+			prev: []Agent{claude("%1", agent.StateIdle)},
+			cur:  nil,
+			want: []Kind{KindDisappeared},
+		},
+		{
+			name: "Another agent in the same pane",
+			prev: []Agent{claude("%1", agent.StateIdle)},
+			cur:  []Agent{codex("%1", agent.StateIdle)},
+			want: []Kind{KindDisappeared, KindAppeared},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.prev, tt.cur, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Diff() = %+v, want kinds %v", got, tt.want)
+			}
+			for i, e := range got {
+				if e.Kind != tt.want[i] {
+					t.Errorf("Diff()[%d].Kind = %s, want %s", i, e.Kind, tt.want[i])
+				}
+				if (e.Kind == KindAppeared) != (e.Old == nil) {
+					t.Errorf("Diff()[%d].Old = %v for %s", i, e.Old, e.Kind)
+				}
+				if (e.Kind == KindDisappeared) != (e.New == nil) {
+					t.Errorf("Diff()[%d].New = %v for %s", i, e.New, e.Kind)
+				}
+			}
+		})
+	}
+}
+
+func TestEvent_JSON(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prev := []Agent{{PaneID: "%1", ServerSocket: "/tmp/tmux-1000/default", Session: "dev", WindowIndex: "2", WindowName: "editor", Type: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug", Status: agent.Status{State: agent.StateRunning}, Since: now}}
+	cur := []Agent{{PaneID: "%1", ServerSocket: "/tmp/tmux-1000/default", Session: "dev", WindowIndex: "2", WindowName: "editor", Type: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug", Status: agent.Status{State: agent.StateIdle}, Since: now}}
+
+	events := Diff(prev, cur, now)
+	if len(events) != 1 {
+		t.Fatalf("Diff() = %+v, want 1 event", events)
+	}
+	got, err := json.Marshal(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kind":"state","time":"2026-01-01T00:00:00Z","agent":{"pane_id":"%1","server_socket":"/tmp/tmux-1000/default","session":"dev","window_index":"2","window_name":"editor","type":"claude","icon":"✻","summary":"Fix login bug","status":{"state":"Idle"},"since":"2026-01-01T00:00:00Z"},"old":{"state":"Running"},"new":{"state":"Idle"}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
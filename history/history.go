@@ -0,0 +1,146 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/internal/statedir"
+)
+
+// maxTransitions is the number of state transitions kept per pane.
+const maxTransitions = 20
+
+// Dir returns the directory of state histories ($XDG_STATE_HOME/tcmux/history).
+func Dir() string {
+	return statedir.Dir("history")
+}
+
+// Key identifies a coding agent instance: the pane and the start time of its process.
+// A new process in the same pane (or a reused pane ID after a server restart) starts a new history.
+type Key struct {
+	PaneID    string
+	StartTime uint64 // Start time of the agent process in clock ticks after boot (0 if not available)
+}
+
+// Transition is an observed change of the agent status.
+type Transition struct {
+	State string    `json:"state"`
+	Mode  string    `json:"mode,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Entry is the state history of a coding agent instance.
+type Entry struct {
+	PaneID      string       `json:"pane_id"`
+	StartTime   uint64       `json:"start_time"`
+	Agent       agent.Type   `json:"agent"`
+	State       string       `json:"state"`
+	Mode        string       `json:"mode,omitempty"`
+	Since       time.Time    `json:"since"`       // When the current state was first observed
+	Transitions []Transition `json:"transitions"` // Oldest first, up to maxTransitions
+}
+
+// Store reads and writes state histories, one file per pane.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store in the directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Default returns the Store in the default directory.
+func Default() *Store {
+	return NewStore(Dir())
+}
+
+// Observe records the observed status of the agent and returns the updated history.
+// The history is written only when the state or mode changes.
+func (s *Store) Observe(key Key, t agent.Type, status agent.Status, now time.Time) (*Entry, error) {
+	path, err := s.path(key.PaneID)
+	if err != nil {
+		return nil, err
+	}
+	e, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil || e.StartTime != key.StartTime || e.Agent != t {
+		e = &Entry{
+			PaneID:    key.PaneID,
+			StartTime: key.StartTime,
+			Agent:     t,
+		}
+	}
+	if e.State == status.State && e.Mode == status.Mode {
+		return e, nil
+	}
+
+	if e.State != status.State {
+		e.Since = now
+	}
+	e.State = status.State
+	e.Mode = status.Mode
+	e.Transitions = append(e.Transitions, Transition{State: status.State, Mode: status.Mode, At: now})
+	if len(e.Transitions) > maxTransitions {
+		e.Transitions = e.Transitions[len(e.Transitions)-maxTransitions:]
+	}
+	if err := save(path, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Load reads the state history of the pane.
+// Returns nil without error if there is no history.
+func (s *Store) Load(paneID string) (*Entry, error) {
+	path, err := s.path(paneID)
+	if err != nil {
+		return nil, err
+	}
+	return load(path)
+}
+
+// path returns the file path of the history: <dir>/<pane number>[@<socket name>-<hash>].json
+func (s *Store) path(paneID string) (string, error) {
+	if s.dir == "" {
+		return "", errors.New("state history directory is not available")
+	}
+	id := strings.TrimPrefix(paneID, "%")
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid pane id: %q", paneID)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+func load(path string) (*Entry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("invalid state history %s: %w", path, err)
+	}
+	return &e, nil
+}
+
+// save writes the history atomically.
+func save(path string, e *Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(path, b)
+}
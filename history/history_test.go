@@ -0,0 +1,132 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestStore_Observe(t *testing.T) {
+	store := NewStore(t.TempDir())
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := Key{PaneID: "%1", StartTime: 100}
+
+	steps := []struct {
+		name            string
+		key             Key
+		agent           agent.Type
+		status          agent.Status
+		at              time.Duration
+		wantSince       time.Duration
+		wantTransitions int
+	}{
+		{
+			name:            "First observation",
+			key:             key,
+			agent:           agent.TypeClaude,
+			status:          agent.Status{State: agent.StateRunning},
+			at:              0,
+			wantSince:       0,
+			wantTransitions: 1,
+		},
+		{
+			name:            "Same state keeps since",
+			key:             key,
+			agent:           agent.TypeClaude,
+			status:          agent.Status{State: agent.StateRunning, Description: "5s"},
+			at:              5 * time.Second,
+			wantSince:       0,
+			wantTransitions: 1,
+		},
+		{
+			name:            "Mode change keeps since",
+			key:             key,
+			agent:           agent.TypeClaude,
+			status:          agent.Status{State: agent.StateRunning, Mode: agent.ModePlan},
+			at:              10 * time.Second,
+			wantSince:       0,
+			wantTransitions: 2,
+		},
+		{
+			name:            "State change resets since",
+			key:             key,
+			agent:           agent.TypeClaude,
+			status:          agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan},
+			at:              time.Minute,
+			wantSince:       time.Minute,
+			wantTransitions: 3,
+		},
+		{
+			name:            "New process starts a new history",
+			key:             Key{PaneID: "%1", StartTime: 200},
+			agent:           agent.TypeClaude,
+			status:          agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan},
+			at:              2 * time.Minute,
+			wantSince:       2 * time.Minute,
+			wantTransitions: 1,
+		},
+		{
+			name:            "Another agent starts a new history",
+			key:             Key{PaneID: "%1", StartTime: 200},
+			agent:           agent.TypeCodex,
+			status:          agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan},
+			at:              3 * time.Minute,
+			wantSince:       3 * time.Minute,
+			wantTransitions: 1,
+		},
+	}
+
+	for _, st := range steps {
+		t.Run(st.name, func(t *testing.T) {
+			e, err := store.Observe(st.key, st.agent, st.status, t0.Add(st.at))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := t0.Add(st.wantSince); !e.Since.Equal(want) {
+				t.Errorf("Since = %v, want %v", e.Since, want)
+			}
+			if len(e.Transitions) != st.wantTransitions {
+				t.Errorf("len(Transitions) = %d, want %d", len(e.Transitions), st.wantTransitions)
+			}
+			got, err := store.Load(st.key.PaneID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil || got.State != st.status.State || !got.Since.Equal(e.Since) {
+				t.Errorf("Load() = %+v, want state %s since %v", got, st.status.State, e.Since)
+			}
+		})
+	}
+}
+
+func TestStore_ObserveLimitsTransitions(t *testing.T) {
+	store := NewStore(t.TempDir())
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := Key{PaneID: "%1", StartTime: 100}
+	states := []string{agent.StateRunning, agent.StateIdle}
+
+	var e *Entry
+	for i := range maxTransitions + 5 {
+		var err error
+		e, err = store.Observe(key, agent.TypeClaude, agent.Status{State: states[i%2]}, t0.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(e.Transitions) != maxTransitions {
+		t.Fatalf("len(Transitions) = %d, want %d", len(e.Transitions), maxTransitions)
+	}
+	if want := t0.Add(5 * time.Second); !e.Transitions[0].At.Equal(want) {
+		t.Errorf("Transitions[0].At = %v, want %v", e.Transitions[0].At, want)
+	}
+}
+
+func TestStore_InvalidPaneID(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, paneID := range []string{"", "%", "%../1", "%1/2"} {
+		if _, err := store.Load(paneID); err == nil {
+			t.Errorf("Load(%q) error = nil, want error", paneID)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package hook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/internal/statedir"
+)
+
+// Dir returns the directory of hook records ($XDG_STATE_HOME/tcmux/hooks).
+func Dir() string {
+	return statedir.Dir("hooks")
+}
+
+// Store reads and writes hook records, one file per agent type and pane.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store in the directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Default returns the Store in the default directory.
+func Default() *Store {
+	return NewStore(Dir())
+}
+
+// Save writes the hook record atomically.
+func (s *Store) Save(rec *agent.HookRecord) error {
+	path, err := s.path(rec.Agent, rec.PaneID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(path, rec)
+}
+
+// Load reads the hook record of the pane.
+// Returns nil without error if there is no record.
+func (s *Store) Load(t agent.Type, paneID string) (*agent.HookRecord, error) {
+	path, err := s.path(t, paneID)
+	if err != nil {
+		return nil, err
+	}
+	var rec agent.HookRecord
+	if ok, err := readJSON(path, &rec); err != nil || !ok {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// Remove deletes the hook record of the pane.
+func (s *Store) Remove(t agent.Type, paneID string) error {
+	path, err := s.path(t, paneID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SaveSession writes the session info of the pane atomically.
+func (s *Store) SaveSession(t agent.Type, paneID string, info *agent.SessionInfo) error {
+	path, err := s.sessionPath(t, paneID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(path, info)
+}
+
+// LoadSession reads the session info of the pane.
+// Returns nil without error if there is no session info.
+func (s *Store) LoadSession(t agent.Type, paneID string) (*agent.SessionInfo, error) {
+	path, err := s.sessionPath(t, paneID)
+	if err != nil {
+		return nil, err
+	}
+	var info agent.SessionInfo
+	if ok, err := readJSON(path, &info); err != nil || !ok {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// RemoveSession deletes the session info of the pane.
+func (s *Store) RemoveSession(t agent.Type, paneID string) error {
+	path, err := s.sessionPath(t, paneID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// sessionPath returns the file path of the session info: <dir>/<agent>/<pane number>.session.json
+func (s *Store) sessionPath(t agent.Type, paneID string) (string, error) {
+	path, err := s.path(t, paneID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".json") + ".session.json", nil
+}
+
+// path returns the file path of the record: <dir>/<agent>/<pane number>[@<socket name>-<hash>].json
+func (s *Store) path(t agent.Type, paneID string) (string, error) {
+	if s.dir == "" {
+		return "", errors.New("hook record directory is not available")
+	}
+	id := strings.TrimPrefix(paneID, "%")
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid pane id: %q", paneID)
+	}
+	return filepath.Join(s.dir, string(t), id+".json"), nil
+}
+
+// writeJSON writes v as JSON to the path atomically.
+func writeJSON(path string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return statedir.WriteFile(path, b)
+}
+
+// readJSON reads JSON from the path into v.
+// Returns false without error if the file does not exist.
+func readJSON(path string, v any) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("invalid JSON %s: %w", path, err)
+	}
+	return true, nil
+}
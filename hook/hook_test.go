@@ -0,0 +1,93 @@
+package hook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rec, err := store.Load(agent.TypeClaude, "%1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec != nil {
+		t.Errorf("Load() = %+v, want nil", rec)
+	}
+
+	want := &agent.HookRecord{
+		Agent:     agent.TypeClaude,
+		PaneID:    "%1",
+		SessionID: "abc",
+		Event:     agent.ClaudeHookNotification,
+		State:     agent.StateWaiting,
+		UpdatedAt: now,
+	}
+	if err := store.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load(agent.TypeClaude, "%1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != *want {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	if err := store.Remove(agent.TypeClaude, "%1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.Load(agent.TypeClaude, "%1"); got != nil {
+		t.Errorf("Load() after Remove() = %+v, want nil", got)
+	}
+	if err := store.Remove(agent.TypeClaude, "%1"); err != nil {
+		t.Errorf("Remove() of missing record error = %v, want nil", err)
+	}
+}
+
+func TestStore_Session(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	want := &agent.SessionInfo{
+		SessionID: "abc",
+		Model:     "Opus",
+		CostUSD:   1.5,
+		UpdatedAt: now,
+	}
+	if err := store.SaveSession(agent.TypeClaude, "%1", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.LoadSession(agent.TypeClaude, "%1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != *want {
+		t.Errorf("LoadSession() = %+v, want %+v", got, want)
+	}
+
+	// Session info is stored separately from the hook record
+	if rec, _ := store.Load(agent.TypeClaude, "%1"); rec != nil {
+		t.Errorf("Load() = %+v, want nil", rec)
+	}
+
+	if err := store.RemoveSession(agent.TypeClaude, "%1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.LoadSession(agent.TypeClaude, "%1"); got != nil {
+		t.Errorf("LoadSession() after RemoveSession() = %+v, want nil", got)
+	}
+}
+
+func TestStore_InvalidPaneID(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, paneID := range []string{"", "%", "%../1", "%1/2"} {
+		if _, err := store.Load(agent.TypeClaude, paneID); err == nil {
+			t.Errorf("Load(%q) error = nil, want error", paneID)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package statedir provides the state directory of tcmux and atomic writes of the files in it,
+// shared by the stores of hook records and state histories.
+package statedir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the directory of the name in the state directory ($XDG_STATE_HOME/tcmux/<name>,
+// default: ~/.local/state/tcmux/<name>). Returns "" if the home directory is unknown.
+func Dir(name string) string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "tcmux", name)
+}
+
+// WriteFile writes the data to the file atomically (write to a temporary file and rename),
+// creating the directory if needed.
+func WriteFile(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,46 @@
+package statedir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if got, want := Dir("hooks"), "/tmp/state/tcmux/hooks"; got != want {
+		t.Errorf("Dir() = %q, want %q", got, want)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	if got, want := Dir("history"), "/home/user/.local/state/tcmux/history"; got != want {
+		t.Errorf("Dir() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "claude", "3.json")
+	for _, content := range []string{`{"state":"Running"}`, `{"state":"Idle"}`} {
+		if err := WriteFile(path, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("content = %q, want %q", b, content)
+		}
+	}
+
+	// No temporary files are left
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files = %d, want 1", len(entries))
+	}
+}
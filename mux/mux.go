@@ -0,0 +1,81 @@
+// Package mux defines the interface of terminal multiplexers (tmux, Zellij) that tcmux inspects.
+//
+// Sessions, windows and panes are described by variables named after tmux format variables
+// (e.g., session_name, window_index, pane_id), so the same detectors and formats work with any multiplexer.
+// Multiplexers set the variables they support and leave the others empty.
+package mux
+
+import "context"
+
+// Multiplexer is a terminal multiplexer.
+type Multiplexer interface {
+	// Name returns the name of the multiplexer (e.g., "tmux").
+	Name() string
+	// ListSessions returns the sessions with the values of the variables.
+	ListSessions(ctx context.Context, vars []string) ([]Session, error)
+	// ListWindows returns the windows (tabs) with the values of the variables.
+	ListWindows(ctx context.Context, vars []string, opts ListOptions) ([]Window, error)
+	// ListPanes returns the panes with the values of the variables.
+	ListPanes(ctx context.Context, vars []string, opts ListOptions) ([]Pane, error)
+	// CapturePane returns the content of the pane.
+	CapturePane(ctx context.Context, pane Pane, opts CaptureOptions) (string, error)
+	// FocusPane makes the pane active and switches the current client to it.
+	FocusPane(ctx context.Context, pane Pane) error
+	// SendKeys sends the keys to the pane. Keys are key names (e.g., Enter, Escape, C-c) or text.
+	SendKeys(ctx context.Context, pane Pane, keys ...string) error
+	// PaneKey returns the key of the pane that is unique across sessions and servers,
+	// for records of panes (e.g., hook records and state histories).
+	PaneKey(pane Pane) string
+}
+
+// Pane is a pane with variable values.
+type Pane struct {
+	Vars map[string]string
+}
+
+// Window is a window (tab) with variable values.
+type Window struct {
+	Vars map[string]string
+}
+
+// Session is a session with variable values.
+type Session struct {
+	Vars map[string]string
+}
+
+// ListOptions specifies options for listing windows and panes.
+type ListOptions struct {
+	AllSessions bool   // If true, list from all sessions
+	Target      string // Target session name (empty means current session)
+}
+
+// CaptureOptions specifies options for capturing a pane.
+type CaptureOptions struct {
+	Start       string // First line: a line number (0 is the first visible line, negative is in the history) or "-" for the start of the history. Empty means the first visible line
+	End         string // Last line: a line number or "-" for the end of the visible screen. Empty means the last visible line
+	JoinWrapped bool   // Join wrapped lines and preserve trailing spaces
+	Escapes     bool   // Include escape sequences for text and background attributes
+}
+
+// VarPaneCommand is the variable of the command line of the process running in the pane,
+// set by multiplexers that report it instead of the pane PID (e.g., Zellij).
+const VarPaneCommand = "pane_running_command"
+
+// InternalPaneVars are variables required internally for coding agent detection.
+var InternalPaneVars = []string{
+	"session_name",
+	"window_index",
+	"window_name",
+	"pane_id",
+	"pane_pid",
+	"pane_current_command",
+	"pane_title",
+	"cursor_y",
+}
+
+// InternalSessionVars are variables required internally for session listing.
+var InternalSessionVars = []string{
+	"session_name",
+	"session_windows",
+	"session_attached",
+}
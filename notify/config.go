@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+)
+
+// defaultSinks are the sinks used when the config does not specify any.
+var defaultSinks = []string{SinkTmux, SinkBell}
+
+// defaultRules notify when a task is finished and when an agent needs input.
+var defaultRules = []Rule{
+	{From: agent.StateRunning, To: agent.StateIdle},
+	{To: agent.StateWaiting},
+}
+
+// Config is the user configuration of notifications (notify.yaml).
+type Config struct {
+	Sinks []string `yaml:"sinks"` // Default sinks of the rules
+	Rules []Rule   `yaml:"rules"` // The first matching rule is used
+}
+
+// Rule matches state transitions of coding agents.
+type Rule struct {
+	Agent   agent.Type `yaml:"agent"`   // Agent type (empty matches all)
+	Session string     `yaml:"session"` // Session name pattern (path.Match syntax, empty matches all)
+	From    string     `yaml:"from"`    // Previous state (empty matches all, including a newly detected agent)
+	To      string     `yaml:"to"`      // New state (required)
+	Sinks   []string   `yaml:"sinks"`   // Sinks of the rule (empty uses the default sinks)
+	Mute    bool       `yaml:"mute"`    // Suppress notifications of matching transitions
+}
+
+// DefaultConfigPath returns the path of notify.yaml ($XDG_CONFIG_HOME/tcmux/notify.yaml).
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "tcmux", "notify.yaml")
+}
+
+// LoadConfig loads the notification config from the file.
+// A missing config file is not an error; the default sinks and rules are used.
+func LoadConfig(p string) (*Config, error) {
+	c := &Config{}
+	if p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			if err := yaml.Unmarshal(b, c); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+			}
+		}
+	}
+	if len(c.Sinks) == 0 {
+		c.Sinks = defaultSinks
+	}
+	if len(c.Rules) == 0 {
+		c.Rules = defaultRules
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", p, err)
+	}
+	return c, nil
+}
+
+func (c *Config) validate() error {
+	for _, s := range c.Sinks {
+		if !slices.Contains(sinkNames, s) {
+			return fmt.Errorf("unknown sink: %s", s)
+		}
+	}
+	states := []string{agent.StateIdle, agent.StateRunning, agent.StateWaiting}
+	for i, r := range c.Rules {
+		if !slices.Contains(states, r.To) {
+			return fmt.Errorf("rules[%d]: invalid to: %q", i, r.To)
+		}
+		if r.From != "" && !slices.Contains(states, r.From) {
+			return fmt.Errorf("rules[%d]: invalid from: %q", i, r.From)
+		}
+		if _, err := path.Match(r.Session, ""); err != nil {
+			return fmt.Errorf("rules[%d]: invalid session: %w", i, err)
+		}
+		for _, s := range r.Sinks {
+			if !slices.Contains(sinkNames, s) {
+				return fmt.Errorf("rules[%d]: unknown sink: %s", i, s)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the sinks to notify of the event.
+// Returns nil if no rule matches or the matching rule is muted.
+func (c *Config) Match(e event.Event) []string {
+	if e.New == nil || (e.Kind != event.KindState && e.Kind != event.KindAppeared) {
+		return nil
+	}
+	for _, r := range c.Rules {
+		if !r.match(e) {
+			continue
+		}
+		if r.Mute {
+			return nil
+		}
+		if len(r.Sinks) > 0 {
+			return r.Sinks
+		}
+		return c.Sinks
+	}
+	return nil
+}
+
+func (r *Rule) match(e event.Event) bool {
+	if r.Agent != "" && r.Agent != e.Agent.Type {
+		return false
+	}
+	if r.Session != "" {
+		if ok, _ := path.Match(r.Session, e.Agent.Session); !ok {
+			return false
+		}
+	}
+	if r.From != "" && (e.Old == nil || e.Old.State != r.From) {
+		return false
+	}
+	return e.New.State == r.To
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantSinks []string
+		wantRules int
+		wantErr   bool
+	}{
+		{
+			name:      "Missing file uses defaults",
+			yaml:      "",
+			wantSinks: defaultSinks,
+			wantRules: len(defaultRules),
+		},
+		{
+			name: "Sinks and rules",
+			yaml: `
+sinks: [notify-send, osc9]
+rules:
+  - agent: claude
+    to: Waiting
+    sinks: [bell]
+  - session: "scratch*"
+    to: Idle
+    mute: true
+`,
+			wantSinks: []string{SinkNotifySend, SinkOSC9},
+			wantRules: 2,
+		},
+		{
+			name:    "Unknown sink",
+			yaml:    "sinks: [email]",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid state",
+			yaml:    "rules:\n  - to: Done",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid session pattern",
+			yaml:    "rules:\n  - session: \"[\"\n    to: Idle",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "notify.yaml")
+			if tt.yaml != "" {
+				if err := os.WriteFile(p, []byte(tt.yaml), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c, err := LoadConfig(p)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadConfig() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(c.Sinks, tt.wantSinks) {
+				t.Errorf("Sinks = %v, want %v", c.Sinks, tt.wantSinks)
+			}
+			if len(c.Rules) != tt.wantRules {
+				t.Errorf("len(Rules) = %d, want %d", len(c.Rules), tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestConfig_Match(t *testing.T) {
+	c := &Config{
+		Sinks: []string{SinkTmux},
+		Rules: []Rule{
+			{Session: "scratch*", To: agent.StateIdle, Mute: true},
+			{Agent: agent.TypeCodex, To: agent.StateWaiting, Sinks: []string{SinkNotifySend}},
+			{From: agent.StateRunning, To: agent.StateIdle},
+			{To: agent.StateWaiting},
+		},
+	}
+	transition := func(t agent.Type, session, from, to string) event.Event {
+		e := event.Event{
+			Kind:  event.KindState,
+			Agent: event.Agent{Type: t, Session: session},
+			New:   &agent.Status{State: to},
+		}
+		if from == "" {
+			e.Kind = event.KindAppeared
+		} else {
+			e.Old = &agent.Status{State: from}
+		}
+		return e
+	}
+	tests := []struct {
+		name  string
+		event event.Event
+		want  []string
+	}{
+		{
+			name:  "Task finished",
+			event: transition(agent.TypeClaude, "dev", agent.StateRunning, agent.StateIdle),
+			want:  []string{SinkTmux},
+		},
+		{
+			name:  "Muted session",
+			event: transition(agent.TypeClaude, "scratch-1", agent.StateRunning, agent.StateIdle),
+			want:  nil,
+		},
+		{
+			name:  "Waiting to Idle does not match",
+			event: transition(agent.TypeClaude, "dev", agent.StateWaiting, agent.StateIdle),
+			want:  nil,
+		},
+		{
+			name:  "Agent-specific sinks",
+			event: transition(agent.TypeCodex, "dev", agent.StateRunning, agent.StateWaiting),
+			want:  []string{SinkNotifySend},
+		},
+		{
+			name:  "New agent waiting",
+			event: transition(agent.TypeClaude, "dev", "", agent.StateWaiting),
+			want:  []string{SinkTmux},
+		},
+		{
+			name:  "New idle agent does not match from",
+			event: transition(agent.TypeClaude, "dev", "", agent.StateIdle),
+			want:  nil,
+		},
+		{
+			name:  "Disappeared",
+			event: event.Event{Kind: event.KindDisappeared, Old: &agent.Status{State: agent.StateRunning}},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Match(tt.event)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
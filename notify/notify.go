@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+)
+
+// Sink names
+const (
+	SinkNotifySend = "notify-send" // Desktop notification via notify-send
+	SinkTmux       = "tmux"        // tmux display-message
+	SinkBell       = "bell"        // Terminal bell
+	SinkOSC9       = "osc9"        // OSC 9 desktop notification (iTerm2, WezTerm, Windows Terminal, etc.)
+	SinkOSC777     = "osc777"      // OSC 777 desktop notification (rxvt-unicode, foot, Ghostty, etc.)
+)
+
+var sinkNames = []string{SinkNotifySend, SinkTmux, SinkBell, SinkOSC9, SinkOSC777}
+
+// Notification is a message to notify.
+type Notification struct {
+	Title  string
+	Body   string
+	Socket string // Socket path of the tmux server of the agent, for the tmux sink (empty means the default server)
+}
+
+// Sink delivers notifications.
+type Sink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewSink creates the sink of the name.
+// Terminal sinks (bell and OSC) write to w; if passthrough is true, OSC sequences are
+// wrapped in tmux passthrough sequences so that they reach the outer terminal.
+func NewSink(name string, w io.Writer, passthrough bool) (Sink, error) {
+	switch name {
+	case SinkNotifySend:
+		return &commandSink{name: "notify-send", args: func(n Notification) []string {
+			return []string{"--app-name=tcmux", n.Title, n.Body}
+		}}, nil
+	case SinkTmux:
+		return &commandSink{name: "tmux", args: tmuxArgs}, nil
+	case SinkBell:
+		return &terminalSink{w: w, seq: func(Notification) string { return "\a" }}, nil
+	case SinkOSC9:
+		return &terminalSink{w: w, passthrough: passthrough, seq: func(n Notification) string {
+			return "\x1b]9;" + sanitize(n.Title+": "+n.Body) + "\a"
+		}}, nil
+	case SinkOSC777:
+		return &terminalSink{w: w, passthrough: passthrough, seq: func(n Notification) string {
+			return "\x1b]777;notify;" + sanitize(n.Title) + ";" + sanitize(n.Body) + "\a"
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink: %s", name)
+	}
+}
+
+// commandSink notifies by running a command.
+type commandSink struct {
+	name string
+	args func(n Notification) []string
+}
+
+func (s *commandSink) Notify(ctx context.Context, n Notification) error {
+	if out, err := exec.CommandContext(ctx, s.name, s.args(n)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", s.name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// terminalSink notifies by writing an escape sequence to the terminal.
+type terminalSink struct {
+	w           io.Writer
+	passthrough bool
+	seq         func(n Notification) string
+}
+
+func (s *terminalSink) Notify(_ context.Context, n Notification) error {
+	seq := s.seq(n)
+	if s.passthrough {
+		seq = tmuxPassthrough(seq)
+	}
+	_, err := io.WriteString(s.w, seq)
+	return err
+}
+
+// tmuxArgs returns the arguments of tmux to display the notification on the server of the agent.
+// The message is a tmux format, so "#" is escaped to keep text in titles and summaries
+// (e.g., "#(command)") from being expanded.
+func tmuxArgs(n Notification) []string {
+	var args []string
+	if n.Socket != "" {
+		args = append(args, "-S", n.Socket)
+	}
+	msg := strings.ReplaceAll(n.Title+": "+n.Body, "#", "##")
+	return append(args, "display-message", msg)
+}
+
+// tmuxPassthrough wraps an escape sequence so that tmux passes it through to the outer terminal
+// (requires `set -g allow-passthrough on` in tmux 3.3 or later).
+func tmuxPassthrough(seq string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// sanitize removes control characters and the OSC parameter separator from the text.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// Message builds the notification of the event.
+// Title: "✻ claude finished"
+// Body: "dev:2 editor - Fix login bug"
+func Message(e event.Event) Notification {
+	a := e.Agent
+	var verb string
+	switch e.New.State {
+	case agent.StateIdle:
+		verb = "finished"
+	case agent.StateWaiting:
+		verb = "needs your input"
+	case agent.StateRunning:
+		verb = "started"
+	default:
+		verb = strings.ToLower(e.New.State)
+	}
+	body := fmt.Sprintf("%s:%s %s", a.Session, a.WindowIndex, a.WindowName)
+	switch {
+	case e.New.Message != "":
+		body += " - " + e.New.Message
+	case a.Summary != "":
+		body += " - " + a.Summary
+	}
+	return Notification{
+		Title:  fmt.Sprintf("%s %s %s", a.Icon, a.Type, verb),
+		Body:   body,
+		Socket: a.ServerSocket,
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/tcmux/agent"
+	"github.com/k1LoW/tcmux/event"
+)
+
+func TestTerminalSinks(t *testing.T) {
+	n := Notification{Title: "✻ claude finished", Body: "dev:2 editor - Fix login bug"}
+	tests := []struct {
+		name        string
+		sink        string
+		passthrough bool
+		want        string
+	}{
+		{
+			name: "Bell",
+			sink: SinkBell,
+			want: "\a",
+		},
+		{
+			name: "OSC 9",
+			sink: SinkOSC9,
+			want: "\x1b]9;✻ claude finished: dev:2 editor - Fix login bug\a",
+		},
+		{
+			name: "OSC 777",
+			sink: SinkOSC777,
+			want: "\x1b]777;notify;✻ claude finished;dev:2 editor - Fix login bug\a",
+		},
+		{
+			name:        "OSC 9 through tmux",
+			sink:        SinkOSC9,
+			passthrough: true,
+			want:        "\x1bPtmux;\x1b\x1b]9;✻ claude finished: dev:2 editor - Fix login bug\a\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s, err := NewSink(tt.sink, &buf, tt.passthrough)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Notify(context.Background(), n); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Notify() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTmuxArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		n    Notification
+		want []string
+	}{
+		{
+			name: "Default server",
+			n:    Notification{Title: "✻ claude finished", Body: "dev:2 editor - Fix login bug"},
+			want: []string{"display-message", "✻ claude finished: dev:2 editor - Fix login bug"},
+		},
+		{
+			name: "Formats are escaped",
+			n:    Notification{Title: "✻ claude finished", Body: "dev:2 editor - #(touch /tmp/injected) #{pane_id}"},
+			want: []string{"display-message", "✻ claude finished: dev:2 editor - ##(touch /tmp/injected) ##{pane_id}"},
+		},
+		{
+			name: "Server of the agent",
+			n:    Notification{Title: "✻ claude finished", Body: "dev:2 editor", Socket: "/tmp/tmux-1000/work"},
+			want: []string{"-S", "/tmp/tmux-1000/work", "display-message", "✻ claude finished: dev:2 editor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tmuxArgs(tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("tmuxArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	a := event.Agent{Session: "dev", WindowIndex: "2", WindowName: "editor", Type: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug"}
+	tests := []struct {
+		name string
+		new  agent.Status
+		want Notification
+	}{
+		{
+			name: "Finished",
+			new:  agent.Status{State: agent.StateIdle},
+			want: Notification{Title: "✻ claude finished", Body: "dev:2 editor - Fix login bug"},
+		},
+		{
+			name: "Waiting",
+			new:  agent.Status{State: agent.StateWaiting},
+			want: Notification{Title: "✻ claude needs your input", Body: "dev:2 editor - Fix login bug"},
+		},
+		{
+			name: "Message is preferred over summary",
+			new:  agent.Status{State: agent.StateIdle, Message: "Fixed the bug."},
+			want: Notification{Title: "✻ claude finished", Body: "dev:2 editor - Fixed the bug."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Message(event.Event{Kind: event.KindState, Agent: a, New: &tt.new})
+			if got != tt.want {
+				t.Errorf("Message() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/k1LoW/tcmux/agent"
 	"github.com/muesli/termenv"
 )
 
@@ -27,6 +28,18 @@ var (
 
 	// Codex theme color
 	codexThemeColor termenv.Color
+
+	// Gemini CLI theme color
+	geminiThemeColor termenv.Color
+
+	// Aider theme color
+	aiderThemeColor termenv.Color
+
+	// OpenCode theme color
+	opencodeThemeColor termenv.Color
+
+	// Theme colors of user-defined agents (hex strings, resolved at format time)
+	customThemeColors = map[agent.Type]string{}
 )
 
 func init() {
@@ -35,14 +48,17 @@ func init() {
 
 func initOutput(o *termenv.Output) {
 	output = o
-	idleColor = output.Color("#00B359")         // Green
-	runningColor = output.Color("#E5A000")      // Orange/Yellow
-	waitingColor = output.Color("#5CC8FF")      // Cyan/Light blue - awaiting input
-	unknownColor = output.Color("#666666")      // Dark gray
-	modeColor = output.Color("#B366FF")         // Purple/Magenta
-	claudeThemeColor = output.Color("#E5A000")  // Claude Code orange
-	copilotThemeColor = output.Color("#8534F3") // Copilot purple (official brand color)
-	codexThemeColor = output.Color("#9EB3F1")   // Codex logo color
+	idleColor = output.Color("#00B359")          // Green
+	runningColor = output.Color("#E5A000")       // Orange/Yellow
+	waitingColor = output.Color("#5CC8FF")       // Cyan/Light blue - awaiting input
+	unknownColor = output.Color("#666666")       // Dark gray
+	modeColor = output.Color("#B366FF")          // Purple/Magenta
+	claudeThemeColor = output.Color("#E5A000")   // Claude Code orange
+	copilotThemeColor = output.Color("#8534F3")  // Copilot purple (official brand color)
+	codexThemeColor = output.Color("#9EB3F1")    // Codex logo color
+	geminiThemeColor = output.Color("#4796E3")   // Gemini blue
+	aiderThemeColor = output.Color("#14B014")    // Aider green
+	opencodeThemeColor = output.Color("#FAB283") // OpenCode peach
 }
 
 // SetColorMode sets the color output mode: always, never, or auto.
@@ -60,3 +76,60 @@ func SetColorMode(mode string) error {
 	}
 	return nil
 }
+
+// SetThemeColor sets the theme color of a user-defined agent.
+func SetThemeColor(t agent.Type, color string) {
+	customThemeColors[t] = color
+}
+
+// ColorState colors the text with the color of the agent state.
+func ColorState(state, text string) string {
+	return output.String(text).Foreground(stateColorOf(state)).String()
+}
+
+// ColorMode colors the text with the mode color.
+func ColorMode(text string) string {
+	return output.String(text).Foreground(modeColor).String()
+}
+
+// ColorTheme colors the text with the theme color of the agent type.
+func ColorTheme(t agent.Type, text string) string {
+	return output.String(text).Foreground(themeColorOf(t)).String()
+}
+
+// stateColorOf returns the color of the agent state.
+func stateColorOf(state string) termenv.Color {
+	switch state {
+	case agent.StateIdle:
+		return idleColor
+	case agent.StateRunning:
+		return runningColor
+	case agent.StateWaiting:
+		return waitingColor
+	default:
+		return unknownColor
+	}
+}
+
+// themeColorOf returns the theme color of the agent type.
+func themeColorOf(t agent.Type) termenv.Color {
+	switch t {
+	case agent.TypeClaude:
+		return claudeThemeColor
+	case agent.TypeCopilot:
+		return copilotThemeColor
+	case agent.TypeCodex:
+		return codexThemeColor
+	case agent.TypeGemini:
+		return geminiThemeColor
+	case agent.TypeAider:
+		return aiderThemeColor
+	case agent.TypeOpenCode:
+		return opencodeThemeColor
+	default:
+		if c, ok := customThemeColors[t]; ok {
+			return output.Color(c)
+		}
+		return claudeThemeColor
+	}
+}
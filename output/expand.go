@@ -0,0 +1,592 @@
+package output
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Single-character aliases of tmux variables (e.g., #S for #{session_name}).
+var formatAliases = map[byte]string{
+	'D': "pane_id",
+	'F': "window_flags",
+	'H': "host",
+	'I': "window_index",
+	'P': "pane_index",
+	'S': "session_name",
+	'T': "pane_title",
+	'W': "window_name",
+	'h': "host_short",
+}
+
+// lookupFunc returns the value of a variable.
+type lookupFunc func(name string) (string, bool)
+
+// expander evaluates the tmux format language:
+//
+//	#{var}                        Variable
+//	#{?cond,a,b} #{?c1,a,c2,b,c}  Conditionals (cond is a variable or a format)
+//	#{==:a,b} #{!=:a,b} #{<:a,b}  Comparisons of formats (also >, <= and >=)
+//	#{||:a,b} #{&&:a,b}           Logical operators of formats
+//	#{m:pattern,s} #{m/r:re,s}    fnmatch(3) and regular expression matches
+//	#{=N:var} #{=/N/…:var}        Truncate to N columns (negative from the end), with a marker
+//	#{pN:var}                     Pad to N columns (positive on the right, negative on the left)
+//	#{b:var} #{d:var}             basename(3) and dirname(3)
+//	#{s/re/rep/:var}              Regular expression substitution (\1 for groups, flag i ignores case)
+//	#{t:var} #{t/p:var}           Unix time as a date string or a short pretty string
+//	#{l:text}                     Literal text
+//	#{A:template,separator}       Loop over agent instances, joined with the separator
+//	##, #, and #}                 Literal #, comma and brace
+//
+// Modifiers can be combined with ";" (e.g., #{=10;p10:window_name}).
+type expander struct {
+	lookup  lookupFunc
+	agents  []lookupFunc // Variables of each agent instance for #{A:...} loops
+	collect bool         // Evaluate all branches to collect the referenced variables
+	vars    []string     // Referenced variables (collect mode only)
+	now     time.Time
+}
+
+// expandFormat expands the format with the variables, and the variables of each agent instance in loops.
+// Unknown variables expand to empty strings, as in tmux.
+func expandFormat(format string, lookup lookupFunc, agents ...lookupFunc) string {
+	e := &expander{lookup: lookup, agents: agents, now: time.Now()}
+	return e.expand(format)
+}
+
+// formatVars returns the variables referenced by the format, in order of appearance.
+func formatVars(format string) []string {
+	e := &expander{
+		lookup:  func(string) (string, bool) { return "", false },
+		collect: true,
+		now:     time.Now(),
+	}
+	e.expand(format)
+	return e.vars
+}
+
+func (e *expander) expand(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := s[i+1]
+		switch {
+		case c == '{':
+			end := skipBlock(s, i+2)
+			if end < 0 {
+				// Unterminated block: keep it as is
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(e.block(s[i+2 : end]))
+			i = end
+		case c == '#' || c == ',' || c == '}':
+			b.WriteByte(c)
+			i++
+		case formatAliases[c] != "":
+			b.WriteString(e.value(formatAliases[c]))
+			i++
+		default:
+			// Styles (#[...]) and unknown sequences are kept as is
+			b.WriteByte('#')
+		}
+	}
+	return b.String()
+}
+
+// value returns the value of the variable.
+func (e *expander) value(name string) string {
+	if e.collect {
+		e.vars = append(e.vars, name)
+	}
+	v, _ := e.lookup(name)
+	return v
+}
+
+// operand expands a format, or looks up a variable if it does not contain a format.
+func (e *expander) operand(s string) string {
+	if strings.Contains(s, "#{") {
+		return e.expand(s)
+	}
+	return e.value(s)
+}
+
+// block evaluates the content of #{...}.
+func (e *expander) block(content string) string {
+	if strings.HasPrefix(content, "?") {
+		return e.conditional(content[1:])
+	}
+	if strings.HasPrefix(content, "A:") {
+		return e.agentLoop(content[2:])
+	}
+	mods, body, ok := parseModifiers(content)
+	if !ok {
+		return e.value(content)
+	}
+	if len(mods) == 1 {
+		if r, ok := e.operator(mods[0], body); ok {
+			return r
+		}
+	}
+	return e.modify(mods, body)
+}
+
+// conditional evaluates #{?cond,a,cond2,b,...,default}.
+func (e *expander) conditional(content string) string {
+	parts := splitTopLevel(content, ',')
+	result := ""
+	decided := false
+	for i := 0; i+1 < len(parts); i += 2 {
+		cond := isTrue(e.operand(parts[i]))
+		if decided {
+			continue
+		}
+		if cond {
+			result = e.expand(parts[i+1])
+			decided = true
+			if !e.collect {
+				return result
+			}
+		} else if e.collect {
+			e.expand(parts[i+1])
+		}
+	}
+	if len(parts)%2 == 1 {
+		def := e.expand(parts[len(parts)-1])
+		if !decided {
+			result = def
+		}
+	}
+	return result
+}
+
+// agentLoop evaluates #{A:template,separator}: the template is expanded for each agent instance
+// with its variables, and the results are joined with the separator.
+func (e *expander) agentLoop(content string) string {
+	parts := splitTopLevel(content, ',')
+	template := parts[0]
+	sep := ""
+	if len(parts) > 1 {
+		sep = e.expand(strings.Join(parts[1:], ","))
+	}
+	if e.collect {
+		e.expand(template)
+		return ""
+	}
+
+	results := make([]string, 0, len(e.agents))
+	for _, agentVars := range e.agents {
+		child := &expander{
+			lookup: func(name string) (string, bool) {
+				if v, ok := agentVars(name); ok {
+					return v, true
+				}
+				return e.lookup(name)
+			},
+			now: e.now,
+		}
+		results = append(results, child.expand(template))
+	}
+	return strings.Join(results, sep)
+}
+
+// operator evaluates comparison, logical and match operators. Returns false if mod is not an operator.
+func (e *expander) operator(mod modifier, body string) (string, bool) {
+	switch mod.name {
+	case "==", "!=", "<", ">", "<=", ">=", "||", "&&", "m":
+	default:
+		return "", false
+	}
+	operands := splitTopLevel(body, ',')
+	if len(operands) != 2 {
+		return "", true
+	}
+	a, b := e.expand(operands[0]), e.expand(operands[1])
+
+	var r bool
+	switch mod.name {
+	case "==":
+		r = a == b
+	case "!=":
+		r = a != b
+	case "<":
+		r = a < b
+	case ">":
+		r = a > b
+	case "<=":
+		r = a <= b
+	case ">=":
+		r = a >= b
+	case "||":
+		r = isTrue(a) || isTrue(b)
+	case "&&":
+		r = isTrue(a) && isTrue(b)
+	case "m":
+		r = match(a, b, mod.args)
+	}
+	if r {
+		return "1", true
+	}
+	return "0", true
+}
+
+// modify applies value modifiers to the variable (or format) of the body.
+// Modifiers are applied in the order of tmux: time, basename/dirname, substitution, truncation, padding.
+func (e *expander) modify(mods []modifier, body string) string {
+	literal := false
+	for _, m := range mods {
+		if m.name == "l" {
+			literal = true
+		}
+	}
+	v := body
+	if !literal {
+		v = e.operand(body)
+	}
+
+	for _, m := range mods {
+		if m.name == "t" {
+			v = formatTime(v, m.args, e.now)
+		}
+	}
+	for _, m := range mods {
+		switch m.name {
+		case "b":
+			v = path.Base(v)
+		case "d":
+			v = path.Dir(v)
+		}
+	}
+	for _, m := range mods {
+		if m.name == "s" && len(m.args) >= 2 {
+			pattern := m.args[0]
+			if len(m.args) > 2 && strings.Contains(m.args[2], "i") {
+				pattern = "(?i)" + pattern
+			}
+			if re, err := regexp.Compile(pattern); err == nil {
+				// tmux uses \1 for groups
+				v = re.ReplaceAllString(v, backrefPattern.ReplaceAllString(m.args[1], "$${$1}"))
+			}
+		}
+	}
+	for _, m := range mods {
+		if m.name == "=" && len(m.args) > 0 {
+			n, err := strconv.Atoi(m.args[0])
+			if err != nil {
+				continue
+			}
+			marker := ""
+			if len(m.args) > 1 {
+				marker = m.args[1]
+			}
+			v = truncate(v, n, marker)
+		}
+	}
+	for _, m := range mods {
+		if m.name == "p" && len(m.args) > 0 {
+			if n, err := strconv.Atoi(m.args[0]); err == nil {
+				v = pad(v, n)
+			}
+		}
+	}
+	return v
+}
+
+// modifier is a parsed format modifier (e.g., "=" with args ["10"] for "=10").
+type modifier struct {
+	name string
+	args []string
+}
+
+var (
+	operatorNames  = []string{"==", "!=", "<=", ">=", "||", "&&", "<", ">"}
+	numberPattern  = regexp.MustCompile(`^-?[0-9]+`)
+	backrefPattern = regexp.MustCompile(`\\([0-9])`)
+)
+
+// parseModifiers parses the modifiers before the first top-level colon.
+// Returns false if the content does not start with modifiers (i.e., it is a variable name).
+func parseModifiers(content string) ([]modifier, string, bool) {
+	var mods []modifier
+	s := content
+	for {
+		var m modifier
+		matched := false
+		for _, op := range operatorNames {
+			if strings.HasPrefix(s, op) {
+				m, s, matched = modifier{name: op}, s[len(op):], true
+				break
+			}
+		}
+		if !matched {
+			if s == "" {
+				return nil, "", false
+			}
+			switch c := s[0]; c {
+			case '=', 'p':
+				rest := s[1:]
+				if c == '=' && strings.HasPrefix(rest, "/") {
+					// =/N/marker
+					n := numberPattern.FindString(rest[1:])
+					if n == "" || !strings.HasPrefix(rest[1+len(n):], "/") {
+						return nil, "", false
+					}
+					rest = rest[2+len(n):]
+					end := strings.IndexAny(rest, ":;")
+					if end < 0 {
+						return nil, "", false
+					}
+					m, s = modifier{name: "=", args: []string{n, rest[:end]}}, rest[end:]
+					break
+				}
+				n := numberPattern.FindString(rest)
+				if n == "" {
+					return nil, "", false
+				}
+				m, s = modifier{name: string(c), args: []string{n}}, rest[len(n):]
+			case 's':
+				// The delimiter must be a punctuation character (e.g., s/a/b/), so that variables like session_name are not modifiers
+				if len(s) < 2 || !strings.ContainsRune("/|!#%@+,", rune(s[1])) {
+					return nil, "", false
+				}
+				args, r, ok := parseDelimited(s[1:], 2)
+				if !ok {
+					return nil, "", false
+				}
+				// Optional flags after the last delimiter
+				flags := ""
+				for r != "" && r[0] != ':' && r[0] != ';' {
+					flags += r[:1]
+					r = r[1:]
+				}
+				m, s = modifier{name: "s", args: append(args, flags)}, r
+			case 't', 'm':
+				m, s = modifier{name: string(c)}, s[1:]
+				if strings.HasPrefix(s, "/") {
+					// t/p, t/f/<format>/, m/r, m/ri
+					end := strings.IndexAny(s, ":;")
+					if end < 0 {
+						return nil, "", false
+					}
+					m.args = strings.Split(strings.Trim(s[1:end], "/"), "/")
+					s = s[end:]
+				}
+			case 'b', 'd', 'l':
+				m, s = modifier{name: string(c)}, s[1:]
+			default:
+				return nil, "", false
+			}
+		}
+		mods = append(mods, m)
+		if s == "" {
+			return nil, "", false
+		}
+		switch s[0] {
+		case ':':
+			return mods, s[1:], true
+		case ';':
+			s = s[1:]
+		default:
+			return nil, "", false
+		}
+	}
+}
+
+// parseDelimited parses n arguments delimited by the first character of s (e.g., "/a/b/").
+func parseDelimited(s string, n int) ([]string, string, bool) {
+	if s == "" {
+		return nil, "", false
+	}
+	delim := s[:1]
+	s = s[1:]
+	var args []string
+	for range n {
+		i := strings.Index(s, delim)
+		if i < 0 {
+			return nil, "", false
+		}
+		args = append(args, s[:i])
+		s = s[i+1:]
+	}
+	return args, s, true
+}
+
+// skipBlock returns the index of the brace closing the block whose content starts at i, or -1.
+func skipBlock(s string, i int) int {
+	depth := 1
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '#' && i+1 < len(s) && (s[i+1] == '#' || s[i+1] == ',' || s[i+1] == '}'):
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s by sep outside nested blocks and escapes.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i+1 < len(s) && s[i+1] == '{':
+			end := skipBlock(s, i+2)
+			if end < 0 {
+				return append(parts, s[start:])
+			}
+			i = end
+		case s[i] == '#' && i+1 < len(s):
+			i++
+		case s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// isTrue checks the truthiness of a value as tmux does: non-empty and not "0".
+func isTrue(v string) bool {
+	return v != "" && v != "0"
+}
+
+// match matches the string against the pattern: fnmatch(3), or a regular expression with the r flag.
+func match(pattern, s string, flags []string) bool {
+	f := strings.Join(flags, "")
+	if strings.Contains(f, "r") {
+		if strings.Contains(f, "i") {
+			pattern = "(?i)" + pattern
+		}
+		ok, _ := regexp.MatchString(pattern, s)
+		return ok
+	}
+	if strings.Contains(f, "i") {
+		pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+// formatTime formats a Unix time: ctime(3) style by default, or short with the p flag.
+func formatTime(v string, args []string, now time.Time) string {
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return ""
+	}
+	t := time.Unix(sec, 0).In(now.Location())
+	if len(args) > 0 && args[0] == "p" {
+		age := now.Sub(t)
+		switch {
+		case age < 24*time.Hour:
+			return t.Format("15:04")
+		case age < 7*24*time.Hour:
+			return t.Format("Mon15")
+		case t.Year() == now.Year():
+			return t.Format("02Jan")
+		default:
+			return t.Format("Jan06")
+		}
+	}
+	return t.Format("Mon Jan _2 15:04:05 2006")
+}
+
+// ansiPattern matches SGR escape sequences, which have no width.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// displayWidth returns the display width of s, ignoring escape sequences.
+func displayWidth(s string) int {
+	return runewidth.StringWidth(ansiPattern.ReplaceAllString(s, ""))
+}
+
+// truncate truncates s to n columns (the last -n columns if negative), appending the marker if truncated.
+// Escape sequences are kept.
+func truncate(s string, n int, marker string) string {
+	fromEnd := n < 0
+	if fromEnd {
+		n = -n
+	}
+	if displayWidth(s) <= n {
+		return s
+	}
+
+	// Split into escape sequences and runes
+	type token struct {
+		text  string
+		width int
+	}
+	var tokens []token
+	for len(s) > 0 {
+		if loc := ansiPattern.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			tokens = append(tokens, token{text: s[:loc[1]]})
+			s = s[loc[1]:]
+			continue
+		}
+		r := []rune(s)[0]
+		tokens = append(tokens, token{text: string(r), width: runewidth.RuneWidth(r)})
+		s = s[len(string(r)):]
+	}
+
+	keep := make([]bool, len(tokens))
+	w := 0
+	full := false
+	for k := range tokens {
+		i := k
+		if fromEnd {
+			i = len(tokens) - 1 - k
+		}
+		if tokens[i].width == 0 {
+			keep[i] = true
+			continue
+		}
+		if full || w+tokens[i].width > n {
+			full = true
+			continue
+		}
+		w += tokens[i].width
+		keep[i] = true
+	}
+	var b strings.Builder
+	if fromEnd {
+		b.WriteString(marker)
+	}
+	for i, t := range tokens {
+		if keep[i] {
+			b.WriteString(t.text)
+		}
+	}
+	if !fromEnd {
+		b.WriteString(marker)
+	}
+	return b.String()
+}
+
+// pad pads s to n columns like tmux: on the right if positive, on the left if negative.
+func pad(s string, n int) string {
+	left := n < 0
+	if left {
+		n = -n
+	}
+	w := displayWidth(s)
+	if w >= n {
+		return s
+	}
+	if left {
+		return strings.Repeat(" ", n-w) + s
+	}
+	return s + strings.Repeat(" ", n-w)
+}
@@ -0,0 +1,131 @@
+package output
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExpandFormatLanguage(t *testing.T) {
+	vars := map[string]string{
+		"session_name":      "dev",
+		"session_attached":  "1",
+		"window_index":      "2",
+		"window_name":       "editor",
+		"window_active":     "0",
+		"pane_title":        "✳ Fix login bug",
+		"pane_current_path": "/home/user/src/tcmux",
+		"empty":             "",
+		"colored":           "\x1b[31mredtext\x1b[0m",
+		"wide":              "日本語テキスト",
+		"created":           "1767225600",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		// Variables and escapes
+		{"Variable", "#{session_name}:#{window_index}", "dev:2"},
+		{"Unknown variable", "[#{unknown}]", "[]"},
+		{"Aliases", "#S:#I #W", "dev:2 editor"},
+		{"Escaped hash", "## #{window_index}", "# 2"},
+		{"Style is kept", "#[fg=red]#{window_name}#[default]", "#[fg=red]editor#[default]"},
+		{"Unterminated block is kept", "#{window_name", "#{window_name"},
+
+		// Conditionals
+		{"Conditional true", "#{?session_attached,attached,detached}", "attached"},
+		{"Conditional false", "#{?window_active,active,inactive}", "inactive"},
+		{"Conditional empty is false", "#{?empty,yes,no}", "no"},
+		{"Conditional unknown is false", "#{?unknown,yes,no}", "no"},
+		{"Conditional without else", "#{?window_active,active}", ""},
+		{"Conditional with spaces", "#{?session_attached, (attached),}", " (attached)"},
+		{"Conditional with variables in branches", "#{?session_attached,#{session_name},-}", "dev"},
+		{"Nested conditionals", "#{?session_attached,#{?window_active,A,B},C}", "B"},
+		{"Chained conditionals", "#{?window_active,1,session_attached,2,3}", "2"},
+		{"Conditional on a format", "#{?#{==:#{window_name},editor},yes,no}", "yes"},
+		{"Escaped comma in branch", "#{?session_attached,a#,b,c}", "a,b"},
+		{"Escaped brace in branch", "#{?session_attached,{a#},c}", "{a}"},
+
+		// Operators
+		{"Equal", "#{==:#{session_name},dev}", "1"},
+		{"Not equal", "#{!=:#{session_name},dev}", "0"},
+		{"Less than", "#{<:a,b}", "1"},
+		{"Greater or equal", "#{>=:a,b}", "0"},
+		{"Or", "#{||:#{window_active},#{session_attached}}", "1"},
+		{"And", "#{&&:#{window_active},#{session_attached}}", "0"},
+		{"Match", "#{m:*edit*,#{window_name}}", "1"},
+		{"Match regexp", "#{m/r:^ed.*r$,#{window_name}}", "1"},
+		{"Match ignore case", "#{m/ri:^EDITOR$,#{window_name}}", "1"},
+		{"Operator in conditional", "#{?#{&&:#{session_attached},#{!=:#{window_index},0}},yes,no}", "yes"},
+
+		// Modifiers
+		{"Truncate", "#{=3:window_name}", "edi"},
+		{"Truncate from end", "#{=-3:window_name}", "tor"},
+		{"Truncate shorter", "#{=10:window_name}", "editor"},
+		{"Truncate with marker", "#{=/3/...:window_name}", "edi..."},
+		{"Truncate wide characters", "#{=6:wide}", "日本語"},
+		{"Truncate keeps escape sequences", "#{=3:colored}", "\x1b[31mred\x1b[0m"},
+		{"Pad right", "[#{p8:window_name}]", "[editor  ]"},
+		{"Pad left", "[#{p-8:window_name}]", "[  editor]"},
+		{"Pad longer", "[#{p3:window_name}]", "[editor]"},
+		{"Pad ignores escape sequences", "#{p9:colored}|", "\x1b[31mredtext\x1b[0m  |"},
+		{"Truncate and pad", "[#{=3;p5:window_name}]", "[edi  ]"},
+		{"Basename", "#{b:pane_current_path}", "tcmux"},
+		{"Dirname", "#{d:pane_current_path}", "/home/user/src"},
+		{"Substitute", "#{s/^✳ //:pane_title}", "Fix login bug"},
+		{"Substitute with groups", "#{s/(\\w+)@(\\w+)/\\2/;l:user@host}", "host"},
+		{"Substitute ignore case", "#{s/EDIT/view/i:window_name}", "viewor"},
+		{"Substitute and basename", "#{s/src/lib/;b:pane_current_path}", "tcmux"},
+		{"Literal", "#{l:#{window_name}}", "#{window_name}"},
+		{"Modifier on a format", "#{=3:#{session_name}-#{window_name}}", "dev"},
+		{"Variables that look like modifiers", "#{pane_title} #{session_name}", "✳ Fix login bug dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expandFormat(tt.format, lookup)
+			if got != tt.want {
+				t.Errorf("expandFormat(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		v    string
+		args []string
+		want string
+	}{
+		{"ctime", "1767225600", nil, "Thu Jan  1 00:00:00 2026"},
+		{"Pretty today", "1781521200", []string{"p"}, "11:00"},
+		{"Pretty this week", "1781175600", []string{"p"}, "Thu11"},
+		{"Pretty this year", "1767225600", []string{"p"}, "01Jan"},
+		{"Pretty last year", "1735689600", []string{"p"}, "Jan25"},
+		{"Not a number", "abc", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatTime(tt.v, tt.args, now)
+			if got != tt.want {
+				t.Errorf("formatTime(%q) = %q, want %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatVars(t *testing.T) {
+	got := formatVars("#S #{?session_attached,#{window_name},#{=5:pane_title}} #{==:#{window_index},0} #{l:literal}")
+	want := []string{"session_name", "session_attached", "window_name", "pane_title", "window_index"}
+	if !slices.Equal(got, want) {
+		t.Errorf("formatVars() = %v, want %v", got, want)
+	}
+}
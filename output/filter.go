@@ -0,0 +1,101 @@
+package output
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+// AgentFilter selects coding agent instances. Empty conditions match all.
+type AgentFilter struct {
+	States       []string       // States (Idle, Running, Waiting; case-insensitive)
+	Agents       []agent.Type   // Agent types (case-insensitive)
+	Modes        []string       // Modes (case-insensitive, "plan" matches "plan mode")
+	Sessions     []string       // Session name patterns (path.Match syntax)
+	SummaryMatch *regexp.Regexp // Regular expression for summaries
+}
+
+// NewAgentFilter creates an AgentFilter and validates the conditions.
+func NewAgentFilter(states, agents, modes, sessions []string, summaryMatch string) (*AgentFilter, error) {
+	f := &AgentFilter{Modes: modes, Sessions: sessions}
+	for _, s := range states {
+		switch {
+		case strings.EqualFold(s, agent.StateIdle):
+			f.States = append(f.States, agent.StateIdle)
+		case strings.EqualFold(s, agent.StateRunning):
+			f.States = append(f.States, agent.StateRunning)
+		case strings.EqualFold(s, agent.StateWaiting):
+			f.States = append(f.States, agent.StateWaiting)
+		default:
+			return nil, fmt.Errorf("invalid state: %s (must be Idle, Running, or Waiting)", s)
+		}
+	}
+	for _, a := range agents {
+		f.Agents = append(f.Agents, agent.Type(a))
+	}
+	for _, s := range sessions {
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid session pattern: %s: %w", s, err)
+		}
+	}
+	if summaryMatch != "" {
+		re, err := regexp.Compile(summaryMatch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid summary match: %w", err)
+		}
+		f.SummaryMatch = re
+	}
+	return f, nil
+}
+
+// MatchSession checks if the session matches the session patterns.
+func (f *AgentFilter) MatchSession(session string) bool {
+	if len(f.Sessions) == 0 {
+		return true
+	}
+	for _, p := range f.Sessions {
+		if ok, _ := path.Match(p, session); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Match checks if the coding agent instance in the session matches the filter.
+func (f *AgentFilter) Match(inst AgentInfo, session string) bool {
+	if !f.MatchSession(session) {
+		return false
+	}
+	if len(f.States) > 0 && !slices.Contains(f.States, inst.Status.State) {
+		return false
+	}
+	if len(f.Agents) > 0 && !slices.ContainsFunc(f.Agents, func(a agent.Type) bool {
+		return strings.EqualFold(string(inst.AgentType), string(a))
+	}) {
+		return false
+	}
+	if len(f.Modes) > 0 && !slices.ContainsFunc(f.Modes, func(m string) bool {
+		return strings.EqualFold(inst.Status.Mode, m) || strings.EqualFold(strings.TrimSuffix(inst.Status.Mode, " mode"), m)
+	}) {
+		return false
+	}
+	if f.SummaryMatch != nil && !f.SummaryMatch.MatchString(inst.Summary) {
+		return false
+	}
+	return true
+}
+
+// Filter returns the coding agent instances in the session that match the filter.
+func (f *AgentFilter) Filter(instances []AgentInfo, session string) []AgentInfo {
+	var matched []AgentInfo
+	for _, inst := range instances {
+		if f.Match(inst, session) {
+			matched = append(matched, inst)
+		}
+	}
+	return matched
+}
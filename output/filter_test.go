@@ -0,0 +1,122 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestAgentFilter(t *testing.T) {
+	claude := AgentInfo{AgentType: agent.TypeClaude, Summary: "Fix login bug", Status: agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan}}
+	codex := AgentInfo{AgentType: agent.TypeCodex, Summary: "Refactor parser", Status: agent.Status{State: agent.StateIdle}}
+	tests := []struct {
+		name         string
+		states       []string
+		agents       []string
+		modes        []string
+		sessions     []string
+		summaryMatch string
+		session      string
+		want         []bool // Match of claude and codex
+	}{
+		{
+			name:    "No conditions",
+			session: "dev",
+			want:    []bool{true, true},
+		},
+		{
+			name:    "State (case-insensitive)",
+			states:  []string{"waiting"},
+			session: "dev",
+			want:    []bool{true, false},
+		},
+		{
+			name:    "Agent",
+			agents:  []string{"codex", "copilot"},
+			session: "dev",
+			want:    []bool{false, true},
+		},
+		{
+			name:    "Agent (case-insensitive)",
+			agents:  []string{"Codex"},
+			session: "dev",
+			want:    []bool{false, true},
+		},
+		{
+			name:    "Mode without suffix",
+			modes:   []string{"plan"},
+			session: "dev",
+			want:    []bool{true, false},
+		},
+		{
+			name:    "Mode",
+			modes:   []string{"Accept Edits"},
+			session: "dev",
+			want:    []bool{false, false},
+		},
+		{
+			name:     "Session pattern",
+			sessions: []string{"work-*"},
+			session:  "work-1",
+			want:     []bool{true, true},
+		},
+		{
+			name:     "Session pattern does not match",
+			sessions: []string{"work-*"},
+			session:  "dev",
+			want:     []bool{false, false},
+		},
+		{
+			name:         "Summary match",
+			summaryMatch: "(?i)^fix",
+			session:      "dev",
+			want:         []bool{true, false},
+		},
+		{
+			name:    "Conditions are combined",
+			states:  []string{"Waiting", "Idle"},
+			agents:  []string{"claude"},
+			session: "dev",
+			want:    []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewAgentFilter(tt.states, tt.agents, tt.modes, tt.sessions, tt.summaryMatch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, inst := range []AgentInfo{claude, codex} {
+				if got := f.Match(inst, tt.session); got != tt.want[i] {
+					t.Errorf("Match(%s) = %v, want %v", inst.AgentType, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAgentFilter_CustomAgent(t *testing.T) {
+	inst := AgentInfo{AgentType: agent.Type("MyAgent"), Status: agent.Status{State: agent.StateIdle}}
+	for _, a := range []string{"MyAgent", "myagent"} {
+		f, err := NewAgentFilter(nil, []string{a}, nil, nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.Match(inst, "dev") {
+			t.Errorf("Match(%s) with agent %s = false, want true", inst.AgentType, a)
+		}
+	}
+}
+
+func TestNewAgentFilter_Invalid(t *testing.T) {
+	if _, err := NewAgentFilter([]string{"Done"}, nil, nil, nil, ""); err == nil {
+		t.Errorf("NewAgentFilter() with invalid state error = nil, want error")
+	}
+	if _, err := NewAgentFilter(nil, nil, nil, []string{"["}, ""); err == nil {
+		t.Errorf("NewAgentFilter() with invalid session pattern error = nil, want error")
+	}
+	if _, err := NewAgentFilter(nil, nil, nil, nil, "("); err == nil {
+		t.Errorf("NewAgentFilter() with invalid summary match error = nil, want error")
+	}
+}
@@ -4,40 +4,74 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/k1LoW/tcmux/agent"
-	"github.com/muesli/termenv"
 )
 
 // tcmux custom format variables
 const (
-	VarAgentStatus  = "agent_status"  // Coding agent status (context-dependent output)
-	VarTotalIdle    = "total_idle"    // Total idle count
-	VarTotalRunning = "total_running" // Total running count
-	VarTotalWaiting = "total_waiting" // Total waiting count
-	VarTotalAgents  = "total_agents"  // Total agent count
+	VarAgentStatus      = "agent_status"      // Coding agent status (context-dependent output)
+	VarAgentCmdline     = "agent_cmdline"     // Resolved command line of coding agent processes
+	VarAgentMessage     = "agent_message"     // Last messages reported by coding agents
+	VarAgentModel       = "agent_model"       // Models of coding agents (from session info)
+	VarAgentCostUSD     = "agent_cost_usd"    // Session costs in USD of coding agents (from session info)
+	VarAgentSession     = "agent_session_id"  // Session IDs of coding agents (from session info)
+	VarAgentStateSince  = "agent_state_since" // Unix times when coding agents entered their current state
+	VarAgentIdleFor     = "agent_idle_for"    // How long idle coding agents have been idle
+	VarAgentWaitingFor  = "agent_waiting_for" // How long waiting coding agents have been waiting
+	VarAgentType        = "agent_type"        // Types of coding agents
+	VarAgentIcon        = "agent_icon"        // Icons of coding agents
+	VarAgentSummary     = "agent_summary"     // Summaries of coding agents
+	VarAgentState       = "agent_state"       // States of coding agents
+	VarAgentMode        = "agent_mode"        // Modes of coding agents
+	VarAgentDescription = "agent_description" // Descriptions of the states of coding agents
+	VarAgentPaneID      = "agent_pane_id"     // Pane IDs of coding agents
+	VarTotalIdle        = "total_idle"        // Total idle count
+	VarTotalRunning     = "total_running"     // Total running count
+	VarTotalWaiting     = "total_waiting"     // Total waiting count
+	VarTotalAgents      = "total_agents"      // Total agent count
 )
 
 var (
-	// Pattern to match format variables: #{variable_name}
-	formatVarPattern = regexp.MustCompile(`#\{([^}]+)\}`)
+	// varNamePattern matches tmux variable names (unsupported modifiers are not variables)
+	varNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
 	// tcmux custom variables
 	tcmuxVars = map[string]bool{
-		VarAgentStatus:  true,
-		VarTotalIdle:    true,
-		VarTotalRunning: true,
-		VarTotalWaiting: true,
-		VarTotalAgents:  true,
+		VarAgentStatus:      true,
+		VarAgentCmdline:     true,
+		VarAgentMessage:     true,
+		VarAgentModel:       true,
+		VarAgentCostUSD:     true,
+		VarAgentSession:     true,
+		VarAgentStateSince:  true,
+		VarAgentIdleFor:     true,
+		VarAgentWaitingFor:  true,
+		VarAgentType:        true,
+		VarAgentIcon:        true,
+		VarAgentSummary:     true,
+		VarAgentState:       true,
+		VarAgentMode:        true,
+		VarAgentDescription: true,
+		VarAgentPaneID:      true,
+		VarTotalIdle:        true,
+		VarTotalRunning:     true,
+		VarTotalWaiting:     true,
+		VarTotalAgents:      true,
 	}
 )
 
 // AgentInfo holds info for a single coding agent instance.
 type AgentInfo struct {
+	PaneID    string
 	AgentType agent.Type
 	Icon      string
 	Summary   string
 	Status    agent.Status
+	Since     time.Time          // When the current state was first observed (zero if unknown)
+	Cmdline   string             // Resolved command line of the agent process (empty if not resolved)
+	Session   *agent.SessionInfo // Session info reported by the agent (nil if not reported)
 }
 
 // FormatContext holds data for format expansion.
@@ -70,16 +104,15 @@ type TotalStatsContext struct {
 }
 
 // ExtractTmuxVars extracts tmux variable names from a format string.
+// Variables in all branches of conditionals and in modifiers are included.
 // Returns only tmux variables (excludes tcmux custom variables).
 func ExtractTmuxVars(format string) []string {
-	matches := formatVarPattern.FindAllStringSubmatch(format, -1)
 	seen := make(map[string]bool)
 	var vars []string
 
-	for _, m := range matches {
-		varName := m[1]
-		// Skip tcmux custom variables
-		if tcmuxVars[varName] {
+	for _, varName := range formatVars(format) {
+		// Skip tcmux custom variables and invalid names
+		if tcmuxVars[varName] || !varNamePattern.MatchString(varName) {
 			continue
 		}
 		// Skip already seen
@@ -94,75 +127,139 @@ func ExtractTmuxVars(format string) []string {
 }
 
 // ExpandFormat expands a format string with the given context.
+// Agent variables are joined with ", " for the instances in the window, or refer to
+// a single instance in #{A:template,separator} loops.
 func ExpandFormat(format string, ctx *FormatContext) string {
-	result := format
+	agentVars := agentLookup(ctx.AgentInstances)
+	loop := make([]lookupFunc, 0, len(ctx.AgentInstances))
+	for _, inst := range ctx.AgentInstances {
+		loop = append(loop, agentLookup([]AgentInfo{inst}))
+	}
+	return expandFormat(format, func(varName string) (string, bool) {
+		if val, ok := agentVars(varName); ok {
+			return val, true
+		}
+		// tmux variable - use value from TmuxVars
+		val, ok := ctx.TmuxVars[varName]
+		return val, ok
+	}, loop...)
+}
 
-	// Expand tcmux custom variables
-	result = formatVarPattern.ReplaceAllStringFunc(result, func(match string) string {
-		// Extract variable name from #{var_name}
-		varName := match[2 : len(match)-1]
+// MatchFormat evaluates a filter format with the given context.
+// As with tmux -f, the window matches if the expansion is non-empty and not "0".
+func MatchFormat(filter string, ctx *FormatContext) bool {
+	return isTrue(ExpandFormat(filter, ctx))
+}
 
+// agentLookup returns the lookup of the agent variables of the instances.
+func agentLookup(instances []AgentInfo) lookupFunc {
+	return func(varName string) (string, bool) {
 		switch varName {
 		case VarAgentStatus:
-			return formatAgentStatus(ctx.AgentInstances)
+			return formatAgentStatus(instances), true
+		case VarAgentCmdline:
+			return formatAgentCmdline(instances), true
+		case VarAgentMessage:
+			return formatAgentMessage(instances), true
+		case VarAgentStateSince:
+			return formatAgentStateSince(instances), true
+		case VarAgentIdleFor:
+			return formatAgentStateFor(instances, agent.StateIdle), true
+		case VarAgentWaitingFor:
+			return formatAgentStateFor(instances, agent.StateWaiting), true
+		case VarAgentModel, VarAgentCostUSD, VarAgentSession:
+			return formatAgentSession(instances, varName), true
+		case VarAgentType:
+			return joinAgents(instances, func(inst AgentInfo) string { return string(inst.AgentType) }), true
+		case VarAgentIcon:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.Icon }), true
+		case VarAgentSummary:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.Summary }), true
+		case VarAgentState:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.Status.State }), true
+		case VarAgentMode:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.Status.Mode }), true
+		case VarAgentDescription:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.Status.Description }), true
+		case VarAgentPaneID:
+			return joinAgents(instances, func(inst AgentInfo) string { return inst.PaneID }), true
 		default:
-			// tmux variable - use value from TmuxVars
-			if val, ok := ctx.TmuxVars[varName]; ok {
-				return val
-			}
-			return match // Keep original if not found
+			return "", false
 		}
-	})
+	}
+}
 
-	return result
+// joinAgents joins the non-empty values of the instances with ", ".
+func joinAgents(instances []AgentInfo, value func(inst AgentInfo) string) string {
+	var values []string
+	for _, inst := range instances {
+		if v := value(inst); v != "" {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, ", ")
 }
 
 // ExpandSessionFormat expands a format string for sessions.
 func ExpandSessionFormat(format string, ctx *SessionFormatContext) string {
-	result := format
-
-	result = formatVarPattern.ReplaceAllStringFunc(result, func(match string) string {
-		varName := match[2 : len(match)-1]
-
+	return expandFormat(format, func(varName string) (string, bool) {
 		switch varName {
 		case VarAgentStatus:
-			return formatAgentStats(ctx.IdleCount, ctx.RunningCount, ctx.WaitingCount)
+			return formatAgentStats(ctx.IdleCount, ctx.RunningCount, ctx.WaitingCount), true
 		default:
 			// tmux variable
-			if val, ok := ctx.TmuxVars[varName]; ok {
-				return val
-			}
-			return match
+			val, ok := ctx.TmuxVars[varName]
+			return val, ok
 		}
 	})
-
-	return result
 }
 
 // ExpandStatsFormat expands a format string for total stats.
 func ExpandStatsFormat(format string, ctx *TotalStatsContext) string {
-	result := format
-
-	result = formatVarPattern.ReplaceAllStringFunc(result, func(match string) string {
-		varName := match[2 : len(match)-1]
-
+	return expandFormat(format, func(varName string) (string, bool) {
 		switch varName {
 		case VarAgentStatus:
-			return formatAgentStats(ctx.IdleCount, ctx.RunningCount, ctx.WaitingCount)
+			return formatAgentStats(ctx.IdleCount, ctx.RunningCount, ctx.WaitingCount), true
 		case VarTotalIdle:
-			return fmt.Sprintf("%d", ctx.IdleCount)
+			return fmt.Sprintf("%d", ctx.IdleCount), true
 		case VarTotalRunning:
-			return fmt.Sprintf("%d", ctx.RunningCount)
+			return fmt.Sprintf("%d", ctx.RunningCount), true
 		case VarTotalWaiting:
-			return fmt.Sprintf("%d", ctx.WaitingCount)
+			return fmt.Sprintf("%d", ctx.WaitingCount), true
 		case VarTotalAgents:
-			return fmt.Sprintf("%d", ctx.IdleCount+ctx.RunningCount+ctx.WaitingCount)
+			return fmt.Sprintf("%d", ctx.IdleCount+ctx.RunningCount+ctx.WaitingCount), true
 		default:
-			return match
+			return "", false
 		}
 	})
+}
 
-	return result
+// ExpandStatusLineFormat expands a format string for Claude Code's status line with the session info.
+func ExpandStatusLineFormat(format string, info *agent.SessionInfo) string {
+	return expandFormat(format, func(varName string) (string, bool) {
+		switch varName {
+		case "model":
+			return info.Model, true
+		case "model_id":
+			return info.ModelID, true
+		case "cwd":
+			return info.Cwd, true
+		case "session_id":
+			return info.SessionID, true
+		case "version":
+			return info.Version, true
+		case "cost_usd":
+			return formatCostUSD(info.CostUSD), true
+		case "duration":
+			return agent.FormatDuration(time.Duration(info.DurationMS) * time.Millisecond), true
+		case "lines_added":
+			return fmt.Sprintf("%d", info.LinesAdded), true
+		case "lines_removed":
+			return fmt.Sprintf("%d", info.LinesRemoved), true
+		default:
+			return "", false
+		}
+	})
 }
 
 // formatAgentStatus formats the full coding agent status string for multiple instances.
@@ -180,29 +277,8 @@ func formatAgentStatus(instances []AgentInfo) string {
 		}
 
 		// Get the color for the state and agent theme
-		var stateColor termenv.Color
-		var themeColor termenv.Color
-		switch inst.Status.State {
-		case agent.StateIdle:
-			stateColor = idleColor
-		case agent.StateRunning:
-			stateColor = runningColor
-		case agent.StateWaiting:
-			stateColor = waitingColor
-		default:
-			stateColor = unknownColor
-		}
-
-		switch inst.AgentType {
-		case agent.TypeClaude:
-			themeColor = claudeThemeColor
-		case agent.TypeCopilot:
-			themeColor = copilotThemeColor
-		case agent.TypeCodex:
-			themeColor = codexThemeColor
-		default:
-			themeColor = claudeThemeColor
-		}
+		stateColor := stateColorOf(inst.Status.State)
+		themeColor := themeColorOf(inst.AgentType)
 
 		// Build the status string with colors
 		coloredState := output.String(inst.Status.State).Foreground(stateColor).String()
@@ -241,6 +317,83 @@ func formatAgentStatus(instances []AgentInfo) string {
 	return strings.Join(instanceParts, ", ")
 }
 
+// formatAgentCmdline formats the resolved command lines of coding agent instances.
+// Format: "claude --continue, node /path/to/gemini"
+func formatAgentCmdline(instances []AgentInfo) string {
+	var cmdlines []string
+	for _, inst := range instances {
+		if inst.Cmdline != "" {
+			cmdlines = append(cmdlines, inst.Cmdline)
+		}
+	}
+	return strings.Join(cmdlines, ", ")
+}
+
+// formatAgentMessage formats the last messages reported by coding agent instances.
+// Format: "❂ Added the Gemini detector., ✻ Done."
+func formatAgentMessage(instances []AgentInfo) string {
+	var messages []string
+	for _, inst := range instances {
+		if inst.Status.Message != "" {
+			messages = append(messages, inst.Icon+" "+inst.Status.Message)
+		}
+	}
+	return strings.Join(messages, ", ")
+}
+
+// formatAgentStateSince formats the Unix times when coding agent instances entered their current state.
+// Format: "1767225600, 1767225900"
+func formatAgentStateSince(instances []AgentInfo) string {
+	var values []string
+	for _, inst := range instances {
+		if !inst.Since.IsZero() {
+			values = append(values, fmt.Sprintf("%d", inst.Since.Unix()))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatAgentStateFor formats how long coding agent instances in the state have been in it.
+// Format: "20m, 3m"
+func formatAgentStateFor(instances []AgentInfo, state string) string {
+	var values []string
+	for _, inst := range instances {
+		if inst.Status.State == state && !inst.Since.IsZero() {
+			values = append(values, agent.FormatDuration(time.Since(inst.Since)))
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatAgentSession formats a field of the session info reported by coding agent instances.
+// Format: "Opus, Sonnet" / "1.23, 0.45" / "abc123, def456"
+func formatAgentSession(instances []AgentInfo, varName string) string {
+	var values []string
+	for _, inst := range instances {
+		if inst.Session == nil {
+			continue
+		}
+		var v string
+		switch varName {
+		case VarAgentModel:
+			v = inst.Session.Model
+		case VarAgentCostUSD:
+			v = formatCostUSD(inst.Session.CostUSD)
+		case VarAgentSession:
+			v = inst.Session.SessionID
+		}
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatCostUSD formats a cost in USD with two decimal places.
+func formatCostUSD(cost float64) string {
+	return fmt.Sprintf("%.2f", cost)
+}
+
 // formatAgentStats formats coding agent statistics for a session.
 func formatAgentStats(idle, running, waiting int) string {
 	total := idle + running + waiting
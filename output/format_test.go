@@ -2,6 +2,7 @@ package output
 
 import (
 	"testing"
+	"time"
 
 	"github.com/k1LoW/tcmux/agent"
 )
@@ -32,6 +33,11 @@ func TestExtractTmuxVars(t *testing.T) {
 			format: "#{agent_status} #{agent_status}",
 			want:   nil,
 		},
+		{
+			name:   "Exclude unsupported modifiers",
+			format: "#{!:#{window_name}}",
+			want:   nil,
+		},
 		{
 			name:   "Duplicate variables",
 			format: "#{window_index} #{window_index}",
@@ -42,6 +48,16 @@ func TestExtractTmuxVars(t *testing.T) {
 			format: "plain text",
 			want:   nil,
 		},
+		{
+			name:   "Variables in conditionals",
+			format: "#{?session_attached,#{window_name},#{window_index}}",
+			want:   []string{"session_attached", "window_name", "window_index"},
+		},
+		{
+			name:   "Variables in agent loops",
+			format: "#{A:#{agent_icon} #{pane_title},#{window_name}}",
+			want:   []string{"window_name", "pane_title"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -177,6 +193,17 @@ func TestExpandFormat(t *testing.T) {
 			},
 			want: "❂ Refactor parser [Idle]",
 		},
+		{
+			name:   "Gemini CLI instance",
+			format: "#{agent_status}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeGemini, Icon: "✦", Summary: "", Status: agent.Status{State: agent.StateRunning, Description: "12s"}},
+				},
+			},
+			want: "✦ [Running (12s)]",
+		},
 		{
 			name:   "Mixed agents",
 			format: "#{agent_status}",
@@ -190,6 +217,124 @@ func TestExpandFormat(t *testing.T) {
 			},
 			want: "✻ Fix login bug [Idle], ⬢ [Running], ❂ [Waiting]",
 		},
+		{
+			name:   "Expand agent_cmdline",
+			format: "#{agent_cmdline}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateIdle}, Cmdline: "claude --continue"},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}},
+					{AgentType: agent.TypeGemini, Icon: "✦", Status: agent.Status{State: agent.StateIdle}, Cmdline: "node /usr/lib/node_modules/@google/gemini-cli/dist/index.js"},
+				},
+			},
+			want: "claude --continue, node /usr/lib/node_modules/@google/gemini-cli/dist/index.js",
+		},
+		{
+			name:   "Expand agent_message",
+			format: "#{agent_message}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateRunning}},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle, Description: "finished 3m ago", Message: "Fixed the bug."}},
+				},
+			},
+			want: "❂ Fixed the bug.",
+		},
+		{
+			name:   "Expand agent session info",
+			format: "#{agent_model} $#{agent_cost_usd} #{agent_session_id}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateIdle}, Session: &agent.SessionInfo{SessionID: "abc123", Model: "Opus", CostUSD: 1.234}},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}},
+				},
+			},
+			want: "Opus $1.23 abc123",
+		},
+		{
+			name:   "Expand agent_state_since",
+			format: "#{agent_state_since}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateIdle}, Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}},
+				},
+			},
+			want: "1767225600",
+		},
+		{
+			name:   "Expand agent_idle_for and agent_waiting_for",
+			format: "I:#{agent_idle_for} W:#{agent_waiting_for}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateWaiting}, Since: time.Now().Add(-20*time.Minute - time.Second)},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}, Since: time.Now().Add(-3*time.Minute - time.Second)},
+					{AgentType: agent.TypeGemini, Icon: "✦", Status: agent.Status{State: agent.StateRunning}, Since: time.Now().Add(-time.Minute)},
+				},
+			},
+			want: "I:3m W:20m",
+		},
+		{
+			name:   "Expand per-agent variables",
+			format: "#{agent_type}|#{agent_icon}|#{agent_summary}|#{agent_state}|#{agent_mode}|#{agent_description}|#{agent_pane_id}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{PaneID: "%1", AgentType: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug", Status: agent.Status{State: agent.StateRunning, Mode: agent.ModePlan, Description: "1m 30s"}},
+					{PaneID: "%2", AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}},
+				},
+			},
+			want: "claude, codex|✻, ❂|Fix login bug|Running, Idle|plan mode|1m 30s|%1, %2",
+		},
+		{
+			name:   "Expand agent loop with icons only",
+			format: "#{A:#{agent_icon},}",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateRunning}},
+					{AgentType: agent.TypeCopilot, Icon: "⬢", Status: agent.Status{State: agent.StateIdle}},
+				},
+			},
+			want: "✻⬢",
+		},
+		{
+			name:   "Expand agent loop with separator, conditionals and tmux variables",
+			format: "#{A:#{window_index}.#{agent_pane_id} #{agent_state}#{?agent_mode, (#{agent_mode}),}#{?#{==:#{agent_state},Waiting}, !,}, | }",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{"window_index": "2"},
+				AgentInstances: []AgentInfo{
+					{PaneID: "%1", AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateWaiting, Mode: agent.ModePlan}},
+					{PaneID: "%2", AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateIdle}},
+				},
+			},
+			want: "2.%1 Waiting (plan mode) ! | 2.%2 Idle",
+		},
+		{
+			name:   "Expand agent_status in agent loop",
+			format: "#{A:[#{agent_status}], / }",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+				AgentInstances: []AgentInfo{
+					{AgentType: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug", Status: agent.Status{State: agent.StateIdle}},
+					{AgentType: agent.TypeCodex, Icon: "❂", Status: agent.Status{State: agent.StateRunning}},
+				},
+			},
+			want: "[✻ Fix login bug [Idle]] / [❂ [Running]]",
+		},
+		{
+			name:   "Expand agent loop without agents",
+			format: "[#{A:#{agent_icon}, }]",
+			ctx: &FormatContext{
+				TmuxVars: map[string]string{},
+			},
+			want: "[]",
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,6 +347,33 @@ func TestExpandFormat(t *testing.T) {
 	}
 }
 
+func TestMatchFormat(t *testing.T) {
+	ctx := &FormatContext{
+		TmuxVars: map[string]string{"session_name": "dev", "window_active": "0"},
+		AgentInstances: []AgentInfo{
+			{AgentType: agent.TypeClaude, Icon: "✻", Status: agent.Status{State: agent.StateWaiting}},
+		},
+	}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"#{&&:#{==:#{agent_state},Waiting},#{!=:#{session_name},scratch}}", true},
+		{"#{&&:#{==:#{agent_state},Waiting},#{!=:#{session_name},dev}}", false},
+		{"#{window_active}", false},
+		{"#{unknown}", false},
+		{"#{agent_type}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			if got := MatchFormat(tt.filter, ctx); got != tt.want {
+				t.Errorf("MatchFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExpandSessionFormat(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -360,3 +532,47 @@ func TestExpandStatsFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandStatusLineFormat(t *testing.T) {
+	info := &agent.SessionInfo{
+		SessionID:    "abc123",
+		Model:        "Opus",
+		ModelID:      "claude-opus-4-1",
+		Cwd:          "/home/user/src/tcmux",
+		Version:      "1.0.80",
+		CostUSD:      0.01234,
+		DurationMS:   185000,
+		LinesAdded:   156,
+		LinesRemoved: 23,
+	}
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "Default format",
+			format: "#{model} $#{cost_usd} #{cwd}",
+			want:   "Opus $0.01 /home/user/src/tcmux",
+		},
+		{
+			name:   "All variables",
+			format: "#{model_id} #{session_id} v#{version} #{duration} +#{lines_added} -#{lines_removed}",
+			want:   "claude-opus-4-1 abc123 v1.0.80 3m +156 -23",
+		},
+		{
+			name:   "Unknown variable is empty",
+			format: "#{model}#{unknown}",
+			want:   "Opus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandStatusLineFormat(tt.format, info)
+			if got != tt.want {
+				t.Errorf("ExpandStatusLineFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
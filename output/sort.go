@@ -0,0 +1,184 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+// Window sort orders (tmux list-windows -O)
+const (
+	SortIndex = "index" // Session name, then window index
+	SortName  = "name"  // Window name
+	SortTime  = "time"  // Window activity, most recent first
+)
+
+// Window sort orders by coding agents (list-windows --sort).
+// Windows are sorted by their most urgent agent; windows without agents come last.
+const (
+	SortState      = "state"       // Waiting (oldest first), Idle (most recently finished first), then Running
+	SortElapsed    = "elapsed"     // Time in the current state, longest first
+	SortSession    = "session"     // Session name, then window index
+	SortAgent      = "agent"       // Agent type, then state
+	SortWaitingAge = "waiting-age" // Waiting agents (oldest first), then the order of tmux
+)
+
+// SortVars returns the tmux variables required to sort windows in the order.
+func SortVars(order string) []string {
+	switch order {
+	case SortIndex, SortSession:
+		return []string{"session_name", "window_index"}
+	case SortName:
+		return []string{"window_name"}
+	case SortTime:
+		return []string{"window_activity"}
+	default:
+		return nil
+	}
+}
+
+// ValidateSortOrder validates the window sort order (empty means the order of tmux).
+func ValidateSortOrder(order string) error {
+	switch order {
+	case "", SortIndex, SortName, SortTime:
+		return nil
+	default:
+		return fmt.Errorf("invalid sort order: %s (must be %s, %s, or %s)", order, SortIndex, SortName, SortTime)
+	}
+}
+
+// ValidateAgentSortOrder validates the window sort order by coding agents (empty means the order of tmux).
+func ValidateAgentSortOrder(order string) error {
+	switch order {
+	case "", SortState, SortElapsed, SortSession, SortAgent, SortWaitingAge:
+		return nil
+	default:
+		return fmt.Errorf("invalid sort order: %s (must be %s, %s, %s, %s, or %s)", order, SortState, SortElapsed, SortSession, SortAgent, SortWaitingAge)
+	}
+}
+
+// SortWindows sorts windows in the order, keeping the order of tmux for ties.
+// If reverse is true, the result is reversed (including the order of tmux if order is empty).
+func SortWindows(windows []*FormatContext, order string, reverse bool) {
+	var compare func(a, b *FormatContext) int
+	switch order {
+	case SortIndex, SortSession:
+		compare = func(a, b *FormatContext) int {
+			return cmp.Or(
+				cmp.Compare(a.TmuxVars["session_name"], b.TmuxVars["session_name"]),
+				cmp.Compare(atoi(a.TmuxVars["window_index"]), atoi(b.TmuxVars["window_index"])),
+			)
+		}
+	case SortName:
+		compare = func(a, b *FormatContext) int {
+			return cmp.Compare(a.TmuxVars["window_name"], b.TmuxVars["window_name"])
+		}
+	case SortTime:
+		compare = func(a, b *FormatContext) int {
+			return cmp.Compare(atoi(b.TmuxVars["window_activity"]), atoi(a.TmuxVars["window_activity"]))
+		}
+	case SortState:
+		compare = func(a, b *FormatContext) int {
+			return compareAgents(a, b, compareAttention)
+		}
+	case SortElapsed:
+		compare = func(a, b *FormatContext) int {
+			return compareAgents(a, b, compareOldest)
+		}
+	case SortAgent:
+		compare = func(a, b *FormatContext) int {
+			return compareAgents(a, b, func(x, y AgentInfo) int {
+				return cmp.Or(cmp.Compare(x.AgentType, y.AgentType), compareAttention(x, y))
+			})
+		}
+	case SortWaitingAge:
+		compare = func(a, b *FormatContext) int {
+			return compareAgents(a, b, func(x, y AgentInfo) int {
+				xw, yw := x.Status.State == agent.StateWaiting, y.Status.State == agent.StateWaiting
+				switch {
+				case xw && yw:
+					return compareOldest(x, y)
+				case xw:
+					return -1
+				case yw:
+					return 1
+				default:
+					return 0
+				}
+			})
+		}
+	}
+	if compare != nil {
+		slices.SortStableFunc(windows, compare)
+	}
+	if reverse {
+		slices.Reverse(windows)
+	}
+}
+
+// stateRanks are the ranks of states in the attention order.
+var stateRanks = map[string]int{
+	agent.StateWaiting: 0,
+	agent.StateIdle:    1,
+	agent.StateRunning: 2,
+}
+
+// compareAttention compares agents in the attention order: Waiting (oldest first),
+// Idle (most recently finished first), then Running.
+func compareAttention(a, b AgentInfo) int {
+	ra, ok := stateRanks[a.Status.State]
+	if !ok {
+		ra = len(stateRanks)
+	}
+	rb, ok := stateRanks[b.Status.State]
+	if !ok {
+		rb = len(stateRanks)
+	}
+	if ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+	if a.Status.State == agent.StateIdle {
+		return compareOldest(b, a)
+	}
+	if a.Status.State == agent.StateWaiting {
+		return compareOldest(a, b)
+	}
+	return 0
+}
+
+// compareOldest compares agents by the time they entered the current state, oldest (longest elapsed) first.
+// Agents with unknown times come last.
+func compareOldest(a, b AgentInfo) int {
+	switch {
+	case a.Since.IsZero() && b.Since.IsZero():
+		return 0
+	case a.Since.IsZero():
+		return 1
+	case b.Since.IsZero():
+		return -1
+	}
+	return a.Since.Compare(b.Since)
+}
+
+// compareAgents compares windows by their first agent in the order of compare.
+// Windows without agents come last.
+func compareAgents(a, b *FormatContext, compare func(x, y AgentInfo) int) int {
+	switch {
+	case len(a.AgentInstances) == 0 && len(b.AgentInstances) == 0:
+		return 0
+	case len(a.AgentInstances) == 0:
+		return 1
+	case len(b.AgentInstances) == 0:
+		return -1
+	}
+	return compare(slices.MinFunc(a.AgentInstances, compare), slices.MinFunc(b.AgentInstances, compare))
+}
+
+// atoi converts a numeric tmux variable, treating invalid values as 0.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
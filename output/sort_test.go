@@ -0,0 +1,116 @@
+package output
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestSortWindows(t *testing.T) {
+	newWindows := func() []*FormatContext {
+		return []*FormatContext{
+			{TmuxVars: map[string]string{"session_name": "b", "window_index": "10", "window_name": "logs", "window_activity": "300"}},
+			{TmuxVars: map[string]string{"session_name": "a", "window_index": "2", "window_name": "editor", "window_activity": "100"}},
+			{TmuxVars: map[string]string{"session_name": "b", "window_index": "9", "window_name": "shell", "window_activity": "200"}},
+		}
+	}
+	tests := []struct {
+		order   string
+		reverse bool
+		want    []string // session_name:window_index
+	}{
+		{"", false, []string{"b:10", "a:2", "b:9"}},
+		{"", true, []string{"b:9", "a:2", "b:10"}},
+		{SortIndex, false, []string{"a:2", "b:9", "b:10"}},
+		{SortIndex, true, []string{"b:10", "b:9", "a:2"}},
+		{SortName, false, []string{"a:2", "b:10", "b:9"}},
+		{SortTime, false, []string{"b:10", "b:9", "a:2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			windows := newWindows()
+			SortWindows(windows, tt.order, tt.reverse)
+			var got []string
+			for _, w := range windows {
+				got = append(got, w.TmuxVars["session_name"]+":"+w.TmuxVars["window_index"])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortWindows_Agents(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := func(name string, instances ...AgentInfo) *FormatContext {
+		return &FormatContext{TmuxVars: map[string]string{"window_name": name}, AgentInstances: instances}
+	}
+	inst := func(typ agent.Type, state string, ago time.Duration) AgentInfo {
+		i := AgentInfo{AgentType: typ, Status: agent.Status{State: state}}
+		if ago > 0 {
+			i.Since = now.Add(-ago)
+		}
+		return i
+	}
+	newWindows := func() []*FormatContext {
+		return []*FormatContext{
+			window("shell"),
+			window("running", inst(agent.TypeClaude, agent.StateRunning, 10*time.Minute)),
+			window("idle-old", inst(agent.TypeCodex, agent.StateIdle, time.Hour)),
+			window("waiting-new", inst(agent.TypeClaude, agent.StateWaiting, time.Minute)),
+			window("idle-new", inst(agent.TypeGemini, agent.StateIdle, 2*time.Minute)),
+			window("mixed", inst(agent.TypeClaude, agent.StateRunning, time.Minute), inst(agent.TypeCodex, agent.StateWaiting, 5*time.Minute)),
+		}
+	}
+	tests := []struct {
+		order   string
+		reverse bool
+		want    []string
+	}{
+		{SortState, false, []string{"mixed", "waiting-new", "idle-new", "idle-old", "running", "shell"}},
+		{SortElapsed, false, []string{"idle-old", "running", "mixed", "idle-new", "waiting-new", "shell"}},
+		{SortAgent, false, []string{"waiting-new", "running", "mixed", "idle-old", "idle-new", "shell"}},
+		{SortWaitingAge, false, []string{"mixed", "waiting-new", "running", "idle-old", "idle-new", "shell"}},
+		{SortState, true, []string{"shell", "running", "idle-old", "idle-new", "waiting-new", "mixed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			windows := newWindows()
+			SortWindows(windows, tt.order, tt.reverse)
+			var got []string
+			for _, w := range windows {
+				got = append(got, w.TmuxVars["window_name"])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSortOrder(t *testing.T) {
+	for _, order := range []string{"", SortIndex, SortName, SortTime} {
+		if err := ValidateSortOrder(order); err != nil {
+			t.Errorf("ValidateSortOrder(%q) error = %v", order, err)
+		}
+	}
+	if err := ValidateSortOrder("size"); err == nil {
+		t.Errorf("ValidateSortOrder(%q) error = nil, want error", "size")
+	}
+}
+
+func TestValidateAgentSortOrder(t *testing.T) {
+	for _, order := range []string{"", SortState, SortElapsed, SortSession, SortAgent, SortWaitingAge} {
+		if err := ValidateAgentSortOrder(order); err != nil {
+			t.Errorf("ValidateAgentSortOrder(%q) error = %v", order, err)
+		}
+	}
+	if err := ValidateAgentSortOrder(SortName); err == nil {
+		t.Errorf("ValidateAgentSortOrder(%q) error = nil, want error", SortName)
+	}
+}
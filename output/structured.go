@@ -0,0 +1,169 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/tcmux/agent"
+)
+
+// SchemaVersion is the version of the structured output schema.
+// It is incremented on incompatible changes (removed or renamed fields, changed types).
+const SchemaVersion = 1
+
+// Structured output formats
+const (
+	FormatJSON   = "json"   // A single JSON document
+	FormatNDJSON = "ndjson" // One JSON object per window, session or stats
+	FormatYAML   = "yaml"   // A single YAML document
+)
+
+// AgentData is a coding agent instance in structured output.
+type AgentData struct {
+	Type        agent.Type `json:"type" yaml:"type"`
+	Icon        string     `json:"icon" yaml:"icon"`
+	Summary     string     `json:"summary" yaml:"summary"`
+	State       string     `json:"state" yaml:"state"`
+	Mode        string     `json:"mode" yaml:"mode"`
+	Description string     `json:"description" yaml:"description"`
+	PaneID      string     `json:"pane_id" yaml:"pane_id"`
+}
+
+// WindowData is a tmux window in structured output.
+type WindowData struct {
+	ServerSocket string      `json:"server_socket" yaml:"server_socket"`
+	SessionName  string      `json:"session_name" yaml:"session_name"`
+	WindowIndex  string      `json:"window_index" yaml:"window_index"`
+	WindowName   string      `json:"window_name" yaml:"window_name"`
+	Agents       []AgentData `json:"agents" yaml:"agents"`
+}
+
+// SessionData is a tmux session in structured output.
+type SessionData struct {
+	ServerSocket string `json:"server_socket" yaml:"server_socket"`
+	SessionName  string `json:"session_name" yaml:"session_name"`
+	Windows      int    `json:"windows" yaml:"windows"`
+	Attached     bool   `json:"attached" yaml:"attached"`
+	Idle         int    `json:"idle" yaml:"idle"`
+	Running      int    `json:"running" yaml:"running"`
+	Waiting      int    `json:"waiting" yaml:"waiting"`
+}
+
+// StatsData is the total coding agent stats in structured output.
+type StatsData struct {
+	Idle    int `json:"idle" yaml:"idle"`
+	Running int `json:"running" yaml:"running"`
+	Waiting int `json:"waiting" yaml:"waiting"`
+	Total   int `json:"total" yaml:"total"`
+}
+
+// NewAgentData converts a coding agent instance to structured output.
+func NewAgentData(inst AgentInfo) AgentData {
+	return AgentData{
+		Type:        inst.AgentType,
+		Icon:        inst.Icon,
+		Summary:     inst.Summary,
+		State:       inst.Status.State,
+		Mode:        inst.Status.Mode,
+		Description: inst.Status.Description,
+		PaneID:      inst.PaneID,
+	}
+}
+
+// NewStatsData converts total stats to structured output.
+func NewStatsData(ctx *TotalStatsContext) StatsData {
+	return StatsData{
+		Idle:    ctx.IdleCount,
+		Running: ctx.RunningCount,
+		Waiting: ctx.WaitingCount,
+		Total:   ctx.IdleCount + ctx.RunningCount + ctx.WaitingCount,
+	}
+}
+
+// ValidateStructuredFormat checks if the structured output format is supported.
+// An empty format means text output.
+func ValidateStructuredFormat(format string) error {
+	switch format {
+	case "", FormatJSON, FormatNDJSON, FormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s (must be json, ndjson, or yaml)", format)
+	}
+}
+
+// WriteWindows writes windows in the structured output format.
+// JSON and YAML: {"schema_version": 1, "windows": [...]}, NDJSON: one window per line with "schema_version".
+func WriteWindows(w io.Writer, format string, windows []WindowData) error {
+	if windows == nil {
+		windows = []WindowData{}
+	}
+	return writeStructured(w, format, "windows", windows, windows)
+}
+
+// WriteSessions writes sessions in the structured output format.
+// JSON and YAML: {"schema_version": 1, "sessions": [...]}, NDJSON: one session per line with "schema_version".
+func WriteSessions(w io.Writer, format string, sessions []SessionData) error {
+	if sessions == nil {
+		sessions = []SessionData{}
+	}
+	return writeStructured(w, format, "sessions", sessions, sessions)
+}
+
+// WriteStats writes total stats in the structured output format.
+// JSON and YAML: {"schema_version": 1, "stats": {...}}, NDJSON: the stats in one line with "schema_version".
+func WriteStats(w io.Writer, format string, stats StatsData) error {
+	return writeStructured(w, format, "stats", stats, []StatsData{stats})
+}
+
+// writeStructured writes the value under the key with the schema version (JSON and YAML),
+// or each line with the schema version as its first field (NDJSON).
+func writeStructured[T any](w io.Writer, format, key string, v any, lines []T) error {
+	switch format {
+	case FormatJSON:
+		// Keys are sorted by encoding/json, and "schema_version" comes first
+		doc := map[string]any{"schema_version": SchemaVersion, key: v}
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case FormatNDJSON:
+		for _, l := range lines {
+			if err := writeNDJSONLine(w, l); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		doc := yaml.MapSlice{
+			{Key: "schema_version", Value: SchemaVersion},
+			{Key: key, Value: v},
+		}
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	default:
+		return ValidateStructuredFormat(format)
+	}
+}
+
+// writeNDJSONLine writes the object in one line with "schema_version" as its first field.
+func writeNDJSONLine(w io.Writer, v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	fields := bytes.TrimPrefix(buf.Bytes(), []byte("{"))
+	if !bytes.HasPrefix(fields, []byte("}")) {
+		fields = append([]byte(","), fields...)
+	}
+	_, err := fmt.Fprintf(w, `{"schema_version":%d%s`, SchemaVersion, fields)
+	return err
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/k1LoW/tcmux/agent"
+)
+
+func TestWriteWindows(t *testing.T) {
+	windows := []WindowData{
+		{
+			ServerSocket: "/tmp/tmux-1000/default",
+			SessionName:  "dev",
+			WindowIndex:  "2",
+			WindowName:   "editor",
+			Agents: []AgentData{
+				NewAgentData(AgentInfo{PaneID: "%3", AgentType: agent.TypeClaude, Icon: "✻", Summary: "Fix login bug", Status: agent.Status{State: agent.StateRunning, Mode: agent.ModePlan, Description: "1m 30s"}}),
+			},
+		},
+		{ServerSocket: "/tmp/tmux-1000/default", SessionName: "dev", WindowIndex: "3", WindowName: "shell", Agents: []AgentData{}},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		windows []WindowData
+		want    string
+	}{
+		{
+			name:    "JSON",
+			format:  FormatJSON,
+			windows: windows[:1],
+			want: `{
+  "schema_version": 1,
+  "windows": [
+    {
+      "server_socket": "/tmp/tmux-1000/default",
+      "session_name": "dev",
+      "window_index": "2",
+      "window_name": "editor",
+      "agents": [
+        {
+          "type": "claude",
+          "icon": "✻",
+          "summary": "Fix login bug",
+          "state": "Running",
+          "mode": "plan mode",
+          "description": "1m 30s",
+          "pane_id": "%3"
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "NDJSON",
+			format:  FormatNDJSON,
+			windows: windows,
+			want: `{"schema_version":1,"server_socket":"/tmp/tmux-1000/default","session_name":"dev","window_index":"2","window_name":"editor","agents":[{"type":"claude","icon":"✻","summary":"Fix login bug","state":"Running","mode":"plan mode","description":"1m 30s","pane_id":"%3"}]}
+{"schema_version":1,"server_socket":"/tmp/tmux-1000/default","session_name":"dev","window_index":"3","window_name":"shell","agents":[]}
+`,
+		},
+		{
+			name:    "YAML",
+			format:  FormatYAML,
+			windows: windows[1:],
+			want: `schema_version: 1
+windows:
+- server_socket: /tmp/tmux-1000/default
+  session_name: dev
+  window_index: "3"
+  window_name: shell
+  agents: []
+`,
+		},
+		{
+			name:    "Empty JSON",
+			format:  FormatJSON,
+			windows: nil,
+			want: `{
+  "schema_version": 1,
+  "windows": []
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteWindows(&buf, tt.format, tt.windows); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteWindows() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	stats := NewStatsData(&TotalStatsContext{IdleCount: 3, RunningCount: 2, WaitingCount: 1})
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatNDJSON,
+			want:   "{\"schema_version\":1,\"idle\":3,\"running\":2,\"waiting\":1,\"total\":6}\n",
+		},
+		{
+			format: FormatYAML,
+			want:   "schema_version: 1\nstats:\n  idle: 3\n  running: 2\n  waiting: 1\n  total: 6\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteStats(&buf, tt.format, stats); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteStats() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStructuredFormat(t *testing.T) {
+	for _, format := range []string{"", FormatJSON, FormatNDJSON, FormatYAML} {
+		if err := ValidateStructuredFormat(format); err != nil {
+			t.Errorf("ValidateStructuredFormat(%q) error = %v, want nil", format, err)
+		}
+	}
+	if err := ValidateStructuredFormat("xml"); err == nil {
+		t.Errorf("ValidateStructuredFormat(%q) error = nil, want error", "xml")
+	}
+}
@@ -0,0 +1,157 @@
+package proc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned when the process table is not available (e.g., non-Linux systems).
+var ErrUnsupported = errors.New("process table is not available")
+
+const defaultRoot = "/proc"
+
+// Process represents an OS process.
+type Process struct {
+	PID  int
+	PPID int
+	Comm string   // Executable name (from /proc/<pid>/stat)
+	Args []string // Command line arguments (from /proc/<pid>/cmdline)
+
+	// StartTime is the time the process started after system boot, in clock ticks (from /proc/<pid>/stat).
+	// Together with PID, it identifies a process across PID reuse. 0 if not available.
+	StartTime uint64
+}
+
+// Cmdline returns the command line of the process.
+func (p *Process) Cmdline() string {
+	if len(p.Args) == 0 {
+		return p.Comm
+	}
+	return strings.Join(p.Args, " ")
+}
+
+// Table is a snapshot of the process table.
+type Table struct {
+	root     string
+	procs    map[int]*Process
+	children map[int][]int
+}
+
+// Snapshot reads the process table from /proc.
+func Snapshot() (*Table, error) {
+	return snapshot(defaultRoot)
+}
+
+func snapshot(root string) (*Table, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrUnsupported
+		}
+		return nil, err
+	}
+	t := &Table{
+		root:     root,
+		procs:    make(map[int]*Process),
+		children: make(map[int][]int),
+	}
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		p, err := readStat(filepath.Join(root, e.Name(), "stat"))
+		if err != nil {
+			// The process may have exited
+			continue
+		}
+		t.procs[pid] = p
+		t.children[p.PPID] = append(t.children[p.PPID], pid)
+	}
+	return t, nil
+}
+
+// Tree returns the process and its descendants in breadth-first order.
+// Command line arguments are read for each returned process.
+func (t *Table) Tree(pid int) []*Process {
+	if _, ok := t.procs[pid]; !ok {
+		return nil
+	}
+	var result []*Process
+	queue := []int{pid}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		p := t.procs[cur]
+		if p.Args == nil {
+			p.Args = readCmdline(filepath.Join(t.root, strconv.Itoa(cur), "cmdline"))
+		}
+		result = append(result, p)
+		queue = append(queue, t.children[cur]...)
+	}
+	return result
+}
+
+// Get returns the process with the PID, or nil if it is not in the table.
+func (t *Table) Get(pid int) *Process {
+	return t.procs[pid]
+}
+
+// statStartTimeField is the index of starttime in the fields after comm (field 22 of /proc/<pid>/stat).
+const statStartTimeField = 19
+
+// readStat parses /proc/<pid>/stat.
+// Format: "pid (comm) state ppid ... starttime ...". comm may contain spaces and parentheses.
+func readStat(path string) (*Process, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	open := strings.Index(s, "(")
+	end := strings.LastIndex(s, ")")
+	if open < 0 || end < open {
+		return nil, fmt.Errorf("invalid stat: %s", path)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(s[:open]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid stat: %s: %w", path, err)
+	}
+	fields := strings.Fields(s[end+1:])
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid stat: %s", path)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid stat: %s: %w", path, err)
+	}
+	var startTime uint64
+	if len(fields) > statStartTimeField {
+		startTime, _ = strconv.ParseUint(fields[statStartTimeField], 10, 64)
+	}
+	return &Process{
+		PID:       pid,
+		PPID:      ppid,
+		Comm:      s[open+1 : end],
+		StartTime: startTime,
+	}, nil
+}
+
+// readCmdline parses /proc/<pid>/cmdline (NUL-separated arguments).
+func readCmdline(path string) []string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return []string{}
+	}
+	b = bytes.TrimRight(b, "\x00")
+	if len(b) == 0 {
+		return []string{}
+	}
+	return strings.Split(string(b), "\x00")
+}
@@ -0,0 +1,109 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeProc(t *testing.T, root string, pid, ppid int, comm string, args ...string) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.Itoa(pid))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stat := strconv.Itoa(pid) + " (" + comm + ") S " + strconv.Itoa(ppid) + " 1 1 0 -1"
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmdline := strings.Join(args, "\x00") + "\x00"
+	if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTableTree(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 100, 1, "zsh", "-zsh")
+	writeProc(t, root, 200, 100, "npm exec claude", "npm", "exec", "claude")
+	writeProc(t, root, 300, 200, "node", "node", "/usr/lib/node_modules/@anthropic-ai/claude-code/cli.js", "--continue")
+	writeProc(t, root, 400, 1, "zsh", "-zsh")
+	if err := os.MkdirAll(filepath.Join(root, "self"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := snapshot(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := table.Tree(100)
+	var got []int
+	for _, p := range tree {
+		got = append(got, p.PID)
+	}
+	want := []int{100, 200, 300}
+	if len(got) != len(want) {
+		t.Fatalf("Tree(100) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Tree(100) = %v, want %v", got, want)
+		}
+	}
+
+	if tree[1].Comm != "npm exec claude" {
+		t.Errorf("Comm = %q, want %q", tree[1].Comm, "npm exec claude")
+	}
+	if want := "node /usr/lib/node_modules/@anthropic-ai/claude-code/cli.js --continue"; tree[2].Cmdline() != want {
+		t.Errorf("Cmdline() = %q, want %q", tree[2].Cmdline(), want)
+	}
+
+	if tree := table.Tree(999); tree != nil {
+		t.Errorf("Tree(999) = %v, want nil", tree)
+	}
+}
+
+func TestSnapshotUnsupported(t *testing.T) {
+	_, err := snapshot(filepath.Join(t.TempDir(), "proc"))
+	if err != ErrUnsupported {
+		t.Errorf("snapshot() error = %v, want %v", err, ErrUnsupported)
+	}
+}
+
+func TestReadStatStartTime(t *testing.T) {
+	tests := []struct {
+		name string
+		stat string
+		want uint64
+	}{
+		{
+			name: "Full stat",
+			stat: "1234 (node (v22)) S 1000 1234 1000 34816 1234 4194304 1589 0 0 0 12 3 0 0 20 0 11 0 987654 1234567 8910 18446744073709551615",
+			want: 987654,
+		},
+		{
+			name: "Truncated stat",
+			stat: "1234 (node) S 1000 1 1 0 -1",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stat")
+			if err := os.WriteFile(path, []byte(tt.stat), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			p, err := readStat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.StartTime != tt.want {
+				t.Errorf("StartTime = %d, want %d", p.StartTime, tt.want)
+			}
+		})
+	}
+}
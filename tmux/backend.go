@@ -0,0 +1,64 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/tcmux/mux"
+)
+
+// Backend is the tmux implementation of mux.Multiplexer.
+// It queries the tmux servers set by SetSockets.
+type Backend struct{}
+
+var _ mux.Multiplexer = Backend{}
+
+// Name returns "tmux".
+func (Backend) Name() string {
+	return "tmux"
+}
+
+// ListSessions returns the tmux sessions with the values of the variables.
+func (Backend) ListSessions(ctx context.Context, vars []string) ([]mux.Session, error) {
+	return ListSessions(ctx, Format(vars), vars)
+}
+
+// ListWindows returns the tmux windows with the values of the variables.
+func (Backend) ListWindows(ctx context.Context, vars []string, opts mux.ListOptions) ([]mux.Window, error) {
+	return ListWindows(ctx, Format(vars), vars, opts)
+}
+
+// ListPanes returns the tmux panes with the values of the variables.
+func (Backend) ListPanes(ctx context.Context, vars []string, opts mux.ListOptions) ([]mux.Pane, error) {
+	return ListPanes(ctx, Format(vars), vars, opts)
+}
+
+// CapturePane captures the content of the pane.
+func (Backend) CapturePane(ctx context.Context, pane mux.Pane, opts mux.CaptureOptions) (string, error) {
+	return CapturePane(ctx, pane.Vars[VarServerSocket], pane.Vars["pane_id"], opts)
+}
+
+// FocusPane switches the current client to the pane (see SwitchToPane).
+func (Backend) FocusPane(ctx context.Context, pane mux.Pane) error {
+	return SwitchToPane(ctx, pane.Vars[VarServerSocket], pane.Vars["pane_id"])
+}
+
+// SendKeys sends the keys to the pane.
+func (Backend) SendKeys(ctx context.Context, pane mux.Pane, keys ...string) error {
+	return SendKeys(ctx, pane.Vars[VarServerSocket], pane.Vars["pane_id"], keys...)
+}
+
+// PaneKey returns the key of the pane (see PaneKey).
+func (Backend) PaneKey(pane mux.Pane) string {
+	return PaneKey(pane.Vars[VarServerSocket], pane.Vars["pane_id"])
+}
+
+// Format builds the tmux format of the variables, separated by tabs.
+func Format(vars []string) string {
+	parts := make([]string, 0, len(vars))
+	for _, v := range vars {
+		parts = append(parts, fmt.Sprintf("#{%s}", v))
+	}
+	return strings.Join(parts, "\t")
+}
@@ -0,0 +1,259 @@
+package tmux
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// errControlClosed is returned when the control-mode connection is closed.
+var errControlClosed = errors.New("tmux control mode connection closed")
+
+var (
+	controlMu sync.Mutex
+	controls  = make(map[string]*controlClient) // Active control-mode connections by socket path
+)
+
+// Connect opens control-mode (tmux -C) connections to the tmux servers (see SetSockets).
+// While connected, ListPanes, ListSessions and CapturePane are pipelined over the connection
+// instead of forking a tmux process per command. If the connection fails or is closed,
+// they fall back to running tmux commands as processes.
+//
+// The control-mode client attaches read-only to the session of $TMUX_PANE (or the most recently
+// used session) without affecting its size. tmux counts it as attached (e.g., it fires the client-attached
+// hooks and updates #{session_last_attached}), but it is not counted in the listed #{session_attached}.
+// Returns the first error, after trying all servers.
+func Connect(ctx context.Context) error {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	var errs []error
+	for _, socket := range Sockets() {
+		if controls[socket] != nil {
+			continue
+		}
+		target := ""
+		if pane := os.Getenv("TMUX_PANE"); pane != "" && socket == DefaultSocket() && os.Getenv("TMUX") != "" {
+			target = pane
+		}
+		c, err := newControlClient(ctx, socket, target, "no-output,read-only,ignore-size", nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		controls[socket] = c
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Disconnect closes the control-mode connections if any.
+func Disconnect() {
+	controlMu.Lock()
+	cs := controls
+	controls = make(map[string]*controlClient)
+	controlMu.Unlock()
+	for _, c := range cs {
+		c.stop()
+	}
+}
+
+// query runs a read-only tmux command on the server of the socket over the control-mode connection
+// if connected, or as a tmux process otherwise.
+func query(ctx context.Context, socket string, args ...string) ([]byte, error) {
+	controlMu.Lock()
+	c := controls[socket]
+	controlMu.Unlock()
+	if c != nil {
+		out, err := c.run(ctx, args...)
+		if !errors.Is(err, errControlClosed) {
+			return out, err
+		}
+		// The connection is lost (e.g., the tmux server exited): fall back to processes
+		controlMu.Lock()
+		if controls[socket] == c {
+			delete(controls, socket)
+		}
+		controlMu.Unlock()
+	}
+	return exec.CommandContext(ctx, "tmux", append(socketArgs(socket), args...)...).Output()
+}
+
+// controlReply is the reply to a command in control mode.
+type controlReply struct {
+	output []byte
+	err    error
+}
+
+// controlClient is a tmux control-mode client.
+// Commands are written in order and replies (%begin ... %end or %error) are matched in order.
+type controlClient struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	notify func(line string) // Called with notifications (nil to ignore them)
+
+	mu      sync.Mutex // Serializes writes and the pending replies
+	pending []chan controlReply
+	done    chan struct{} // Closed when the connection is closed
+}
+
+// newControlClient starts a control-mode client of the server of the socket, attached to the target session
+// (empty means the most recently used one) with the client flags (e.g., no-output, read-only).
+func newControlClient(ctx context.Context, socket, target, flags string, notify func(line string)) (*controlClient, error) {
+	args := append(socketArgs(socket), "-C", "attach-session", "-f", flags)
+	if target != "" {
+		args = append(args, "-t", target)
+	}
+	cmd := exec.CommandContext(ctx, "tmux", args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	c := &controlClient{
+		cmd:    cmd,
+		stdin:  stdin,
+		notify: notify,
+		done:   make(chan struct{}),
+	}
+	go c.read(stdout)
+
+	// The first command confirms the connection
+	if _, err := c.run(ctx, "display-message", "-p", "#{session_name}"); err != nil {
+		c.stop()
+		return nil, fmt.Errorf("failed to start tmux control mode: %w", err)
+	}
+	return c, nil
+}
+
+// run sends a command and waits for its reply.
+func (c *controlClient) run(ctx context.Context, args ...string) ([]byte, error) {
+	ch := make(chan controlReply, 1)
+	c.mu.Lock()
+	select {
+	case <-c.done:
+		c.mu.Unlock()
+		return nil, errControlClosed
+	default:
+	}
+	c.pending = append(c.pending, ch)
+	_, err := io.WriteString(c.stdin, quoteCommand(args)+"\n")
+	if err != nil {
+		c.pending = c.pending[:len(c.pending)-1]
+		c.mu.Unlock()
+		return nil, errControlClosed
+	}
+	c.mu.Unlock()
+
+	select {
+	case r := <-ch:
+		return r.output, r.err
+	case <-c.done:
+		return nil, errControlClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// read dispatches the replies of the connection to the pending commands until it is closed.
+func (c *controlClient) read(r io.Reader) {
+	_ = parseControl(r, func(output []byte, failed bool) {
+		c.mu.Lock()
+		if len(c.pending) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		ch := c.pending[0]
+		c.pending = c.pending[1:]
+		c.mu.Unlock()
+
+		var err error
+		if failed {
+			err = fmt.Errorf("tmux: %s", strings.TrimSpace(string(output)))
+		}
+		ch <- controlReply{output: output, err: err}
+	}, c.notify)
+	c.mu.Lock()
+	close(c.done)
+	c.mu.Unlock()
+}
+
+// stop closes stdin, which makes tmux detach the client, and waits for it to exit.
+func (c *controlClient) stop() {
+	_ = c.stdin.Close()
+	<-c.done
+	_ = c.cmd.Wait()
+}
+
+// parseControl parses the output of a control-mode client and calls reply with the output of
+// each command sent by the client, and notify (if not nil) with each notification line.
+// Replies to other commands (e.g., the initial attach-session) are skipped.
+// Returns when the output ends (at %exit or EOF).
+func parseControl(r io.Reader, reply func(output []byte, failed bool), notify func(line string)) error {
+	br := bufio.NewReader(r)
+	var (
+		inBlock bool
+		guard   string // "<time> <number>" of the current %begin
+		ours    bool   // The block is a reply to a command sent by the client (flags 1)
+		output  []byte
+	)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if inBlock {
+			// Output lines may look like %end, so the guard must match the %begin
+			if fields := strings.Fields(line); len(fields) == 4 && (fields[0] == "%end" || fields[0] == "%error") && fields[1]+" "+fields[2] == guard {
+				if ours {
+					reply(output, fields[0] == "%error")
+				}
+				inBlock, output = false, nil
+				continue
+			}
+			output = append(output, line...)
+			output = append(output, '\n')
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 4 && fields[0] == "%begin":
+			inBlock = true
+			guard = fields[1] + " " + fields[2]
+			ours = fields[3] == "1"
+			output = []byte{}
+		case len(fields) > 0 && fields[0] == "%exit":
+			return nil
+		case notify != nil && strings.HasPrefix(line, "%"):
+			notify(line)
+		}
+	}
+}
+
+// quoteCommand quotes the arguments for the tmux command parser.
+func quoteCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,96 @@
+package tmux
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseControl(t *testing.T) {
+	type reply struct {
+		output string
+		failed bool
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  []reply
+		notes []string
+	}{
+		{
+			name: "Skip the initial command and notifications",
+			input: `%begin 1700000000 10 0
+%end 1700000000 10 0
+%session-changed $1 dev
+%begin 1700000000 11 1
+dev 1
+%end 1700000000 11 1
+`,
+			want:  []reply{{output: "dev 1\n"}},
+			notes: []string{"%session-changed $1 dev"},
+		},
+		{
+			name: "Error",
+			input: `%begin 1700000000 12 1
+can't find pane: %99
+%error 1700000000 12 1
+`,
+			want: []reply{{output: "can't find pane: %99\n", failed: true}},
+		},
+		{
+			name: "Output that looks like the end of another block",
+			input: `%begin 1700000000 13 1
+%end 1700000000 5 1
+
+$ echo done
+%end 1700000000 13 1
+%begin 1700000000 14 1
+%end 1700000000 14 1
+`,
+			want: []reply{{output: "%end 1700000000 5 1\n\n$ echo done\n"}, {output: ""}},
+		},
+		{
+			name: "Stop at exit",
+			input: `%exit
+%begin 1700000000 15 1
+%end 1700000000 15 1
+`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got   []reply
+				notes []string
+			)
+			if err := parseControl(strings.NewReader(tt.input), func(output []byte, failed bool) {
+				got = append(got, reply{output: string(output), failed: failed})
+			}, func(line string) {
+				notes = append(notes, line)
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(notes, tt.notes) {
+				t.Errorf("notifications = %q, want %q", notes, tt.notes)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d replies %+v, want %d replies %+v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("reply[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteCommand(t *testing.T) {
+	got := quoteCommand([]string{"list-panes", "-F", "#{pane_id}\t#{pane_title}", "it's"})
+	want := `'list-panes' '-F' '#{pane_id}	#{pane_title}' 'it'\''s'`
+	if got != want {
+		t.Errorf("quoteCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package tmux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSocketName is the socket name of the default tmux server.
+const defaultSocketName = "default"
+
+var (
+	socketsMu sync.Mutex
+	sockets   []string // Socket paths of the tmux servers to query (nil means the default server)
+)
+
+// invalidKeyChars matches the characters that cannot be used in pane keys.
+var invalidKeyChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// SetSockets sets the socket paths of the tmux servers that ListPanes and ListSessions query
+// and aggregate. Empty means the default server.
+func SetSockets(paths []string) {
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+	sockets = paths
+}
+
+// Sockets returns the socket paths of the tmux servers to query.
+func Sockets() []string {
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+	if len(sockets) == 0 {
+		return []string{DefaultSocket()}
+	}
+	return sockets
+}
+
+// SocketDir returns the directory of tmux sockets: $TMUX_TMPDIR/tmux-$UID (default: /tmp/tmux-$UID).
+func SocketDir() string {
+	dir := os.Getenv("TMUX_TMPDIR")
+	if dir == "" {
+		// tmux ignores $TMPDIR
+		dir = "/tmp"
+	}
+	return filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()))
+}
+
+// SocketPath returns the socket path of the tmux server with the socket name (tmux -L).
+func SocketPath(name string) string {
+	return filepath.Join(SocketDir(), name)
+}
+
+// DefaultSocket returns the socket path of the server that tmux uses without -L or -S:
+// the server of $TMUX inside tmux, the default server otherwise.
+func DefaultSocket() string {
+	if env := os.Getenv("TMUX"); env != "" {
+		if path, _, _ := strings.Cut(env, ","); path != "" {
+			return path
+		}
+	}
+	return SocketPath(defaultSocketName)
+}
+
+// DiscoverSockets returns the socket paths of the running tmux servers in SocketDir.
+func DiscoverSockets() ([]string, error) {
+	dir := SocketDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.Type()&fs.ModeSocket == 0 {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		// Skip sockets left by servers that have exited
+		conn, err := net.DialTimeout("unix", path, 100*time.Millisecond)
+		if err != nil {
+			continue
+		}
+		_ = conn.Close()
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// PaneKey returns the key of the pane that is unique across tmux servers, for records of panes
+// (e.g., hook records and state histories). It is the pane ID on the default server, and
+// the pane ID followed by "@", the socket name and a short hash of the socket path on the others
+// (e.g., "%3@work-1a2b3c4d"), since servers in different directories can have the same socket name.
+func PaneKey(socket, paneID string) string {
+	if socket == "" {
+		return paneID
+	}
+	socket = filepath.Clean(socket)
+	if socket == SocketPath(defaultSocketName) {
+		return paneID
+	}
+	sum := sha256.Sum256([]byte(socket))
+	return paneID + "@" + invalidKeyChars.ReplaceAllString(filepath.Base(socket), "_") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// socketArgs returns the arguments of tmux to use the server of the socket.
+func socketArgs(socket string) []string {
+	return []string{"-S", socket}
+}
@@ -0,0 +1,59 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPaneKey(t *testing.T) {
+	t.Setenv("TMUX_TMPDIR", "/tmp")
+	tests := []struct {
+		socket string
+		paneID string
+		want   string
+	}{
+		{"", "%3", "%3"},
+		{SocketPath("default"), "%3", "%3"},
+		{SocketPath("default") + "/", "%3", "%3"},
+		{"/tmp/tmux-1000/work", "%3", "%3@work-4b80121f"},
+		{"/tmp/other/work", "%3", "%3@work-e28ce23a"},
+		{"/tmp/other/default", "%3", "%3@default-e769f9cb"},
+		{"/run/user/1000/my.sock", "%3", "%3@my_sock-3df66f05"},
+	}
+
+	for _, tt := range tests {
+		if got := PaneKey(tt.socket, tt.paneID); got != tt.want {
+			t.Errorf("PaneKey(%q, %q) = %q, want %q", tt.socket, tt.paneID, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultSocket(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMUX_TMPDIR", dir)
+	socketDir := filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()))
+
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"Outside tmux", "", filepath.Join(socketDir, "default")},
+		{"Inside tmux", "/tmp/tmux-1000/work,1234,0", "/tmp/tmux-1000/work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.env)
+			if got := DefaultSocket(); got != tt.want {
+				t.Errorf("DefaultSocket() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := SocketPath("work"), filepath.Join(socketDir, "work"); got != want {
+		t.Errorf("SocketPath() = %q, want %q", got, want)
+	}
+}
@@ -2,15 +2,17 @@ package tmux
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/k1LoW/tcmux/mux"
 )
 
-// ListPanesOptions specifies options for listing panes.
-type ListPanesOptions struct {
-	AllSessions bool   // If true, list panes from all sessions
-	Target      string // Target session name (empty means current session)
-}
+// ListPanesOptions specifies options for listing windows and panes.
+type ListPanesOptions = mux.ListOptions
 
 // CurrentSession returns the name of the current tmux session.
 func CurrentSession(ctx context.Context) (string, error) {
@@ -22,11 +24,15 @@ func CurrentSession(ctx context.Context) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
-// CapturePane captures the content of a pane and returns the last lines.
-func CapturePane(ctx context.Context, paneID string) (string, error) {
-	// Capture the visible pane content
-	cmd := exec.CommandContext(ctx, "tmux", "capture-pane", "-t", paneID, "-p")
-	out, err := cmd.Output()
+// CapturePaneOptions specifies options for capturing a pane (-S, -E, -J and -e of capture-pane).
+type CapturePaneOptions = mux.CaptureOptions
+
+// CapturePane captures the content of a pane on the server of the socket (empty means the first server to query).
+func CapturePane(ctx context.Context, socket, paneID string, opts CapturePaneOptions) (string, error) {
+	if socket == "" {
+		socket = Sockets()[0]
+	}
+	out, err := query(ctx, socket, capturePaneArgs(paneID, opts)...)
 	if err != nil {
 		return "", err
 	}
@@ -34,29 +40,69 @@ func CapturePane(ctx context.Context, paneID string) (string, error) {
 	return string(out), nil
 }
 
-// InternalPaneVars are tmux variables required internally for coding agent detection.
-var InternalPaneVars = []string{
-	"session_name",
-	"window_index",
-	"window_name",
-	"pane_id",
-	"pane_current_command",
-	"pane_title",
+// capturePaneArgs builds the arguments of capture-pane.
+func capturePaneArgs(paneID string, opts CapturePaneOptions) []string {
+	args := []string{"capture-pane", "-p", "-t", paneID}
+	if opts.Start != "" {
+		args = append(args, "-S", opts.Start)
+	}
+	if opts.End != "" {
+		args = append(args, "-E", opts.End)
+	}
+	if opts.JoinWrapped {
+		args = append(args, "-J")
+	}
+	if opts.Escapes {
+		args = append(args, "-e")
+	}
+	return args
 }
 
-// InternalSessionVars are tmux variables required internally for session listing.
-var InternalSessionVars = []string{
-	"session_name",
-	"session_windows",
-	"session_attached",
+// SwitchToPane makes the pane on the server of the socket (empty means the first server to query) active
+// in its window and session, and switches the current client to it.
+// The current client must be a client of the same server.
+func SwitchToPane(ctx context.Context, socket, paneID string) error {
+	if socket == "" {
+		socket = Sockets()[0]
+	}
+	if socket != DefaultSocket() {
+		return fmt.Errorf("cannot switch to pane %s on another tmux server (%s)", paneID, socket)
+	}
+	for _, args := range [][]string{
+		{"select-window", "-t", paneID},
+		{"select-pane", "-t", paneID},
+		{"switch-client", "-t", paneID},
+	} {
+		if out, err := exec.CommandContext(ctx, "tmux", append(socketArgs(socket), args...)...).CombinedOutput(); err != nil {
+			return fmt.Errorf("tmux %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
 }
 
-// Pane represents a tmux pane with variable values.
-type Pane struct {
-	Vars map[string]string
+// SendKeys sends the keys (key names or text, as send-keys) to the pane on the server of the socket
+// (empty means the first server to query).
+func SendKeys(ctx context.Context, socket, paneID string, keys ...string) error {
+	if socket == "" {
+		socket = Sockets()[0]
+	}
+	args := append(socketArgs(socket), "send-keys", "-t", paneID)
+	if out, err := exec.CommandContext(ctx, "tmux", append(args, keys...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux send-keys: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
 }
 
-// ListPanes returns tmux panes with variable values.
+// VarServerSocket is the variable of the socket path of the tmux server of panes and sessions.
+const VarServerSocket = "server_socket"
+
+// Pane represents a tmux pane with variable values.
+type Pane = mux.Pane
+
+// ListPanes returns tmux panes with variable values, aggregated across the tmux servers (see SetSockets).
+// VarServerSocket is set to the socket path of the server of each pane.
+// The current session (neither AllSessions nor Target) is only looked for on the current server
+// if it is one of them. Returns an error only if listing fails on all servers.
 func ListPanes(ctx context.Context, format string, vars []string, opts ListPanesOptions) ([]Pane, error) {
 	args := []string{"list-panes", "-F", format}
 
@@ -68,63 +114,134 @@ func ListPanes(ctx context.Context, format string, vars []string, opts ListPanes
 		args = append(args, "-s")
 	}
 
-	cmd := exec.CommandContext(ctx, "tmux", args...)
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
-
 	var panes []Pane
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
+	err := queryAll(ctx, currentSockets(opts), vars, args, func(varMap map[string]string) {
+		panes = append(panes, Pane{Vars: varMap})
+	})
+	return panes, err
+}
 
-		parts := strings.Split(line, "\t")
-		varMap := make(map[string]string)
-		for i, v := range vars {
-			if i < len(parts) {
-				varMap[v] = parts[i]
-			}
-		}
+// Window represents a tmux window with variable values.
+type Window = mux.Window
 
-		panes = append(panes, Pane{Vars: varMap})
+// ListWindows returns tmux windows with variable values, aggregated across the tmux servers (see SetSockets).
+// VarServerSocket is set to the socket path of the server of each window.
+// The current session (neither AllSessions nor Target) is only looked for on the current server
+// if it is one of them. Returns an error only if listing fails on all servers.
+func ListWindows(ctx context.Context, format string, vars []string, opts ListPanesOptions) ([]Window, error) {
+	args := []string{"list-windows", "-F", format}
+
+	if opts.AllSessions {
+		args = append(args, "-a")
+	} else if opts.Target != "" {
+		args = append(args, "-t", opts.Target)
 	}
 
-	return panes, nil
+	var windows []Window
+	err := queryAll(ctx, currentSockets(opts), vars, args, func(varMap map[string]string) {
+		windows = append(windows, Window{Vars: varMap})
+	})
+	return windows, err
 }
 
 // Session represents a tmux session with variable values.
-type Session struct {
-	Vars map[string]string
-}
+type Session = mux.Session
 
-// ListSessions returns tmux sessions with variable values.
+// ListSessions returns tmux sessions with variable values, aggregated across the tmux servers (see SetSockets).
+// VarServerSocket is set to the socket path of the server of each session.
+// Returns an error only if listing fails on all servers.
 func ListSessions(ctx context.Context, format string, vars []string) ([]Session, error) {
-	cmd := exec.CommandContext(ctx, "tmux", "list-sessions", "-F", format)
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, err
+	var sessions []Session
+	err := queryAll(ctx, Sockets(), vars, []string{"list-sessions", "-F", format}, func(varMap map[string]string) {
+		sessions = append(sessions, Session{Vars: varMap})
+	})
+	return sessions, err
+}
+
+// currentSockets returns the sockets of the servers to list windows or panes from.
+// The current session is only looked for on the current server if it is one of them.
+func currentSockets(opts ListPanesOptions) []string {
+	socks := Sockets()
+	if !opts.AllSessions && opts.Target == "" && slices.Contains(socks, DefaultSocket()) {
+		return []string{DefaultSocket()}
 	}
+	return socks
+}
 
-	var sessions []Session
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if line == "" {
+// queryAll runs a listing command on the tmux servers of the socket paths and calls add with the variables of each line.
+// #{session_attached} does not count the control-mode clients of tcmux (see countAttachedClients).
+// Returns the first error if the command fails on all servers.
+func queryAll(ctx context.Context, paths, vars, args []string, add func(varMap map[string]string)) error {
+	var errs []error
+	for _, socket := range paths {
+		out, err := query(ctx, socket, args...)
+		if err != nil {
+			errs = append(errs, err)
 			continue
 		}
 
-		parts := strings.Split(line, "\t")
-		varMap := make(map[string]string)
-		for i, v := range vars {
-			if i < len(parts) {
-				varMap[v] = parts[i]
+		var varMaps []map[string]string
+		lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+		for _, line := range lines {
+			if line == "" {
+				continue
 			}
+
+			parts := strings.Split(line, "\t")
+			varMap := make(map[string]string)
+			for i, v := range vars {
+				if i < len(parts) {
+					varMap[v] = parts[i]
+				}
+			}
+
+			varMap[VarServerSocket] = socket
+			varMaps = append(varMaps, varMap)
 		}
+		if slices.Contains(vars, "session_attached") {
+			countAttachedClients(ctx, socket, varMaps)
+		}
+		for _, varMap := range varMaps {
+			add(varMap)
+		}
+	}
+	if len(errs) == len(paths) {
+		return errs[0]
+	}
+	return nil
+}
 
-		sessions = append(sessions, Session{Vars: varMap})
+// countAttachedClients recounts #{session_attached} of the variables listed from the server of the socket
+// without the read-only control-mode clients of tcmux, which are attached to sessions (see Connect and Watch)
+// but are not users. The variables are left as is if the clients cannot be listed or #{session_name} is not listed.
+func countAttachedClients(ctx context.Context, socket string, varMaps []map[string]string) {
+	out, err := query(ctx, socket, "list-clients", "-F", "#{client_session}\t#{client_flags}")
+	if err != nil {
+		return
 	}
+	counts := parseAttachedClients(string(out))
+	for _, varMap := range varMaps {
+		if session, ok := varMap["session_name"]; ok {
+			varMap["session_attached"] = strconv.Itoa(counts[session])
+		}
+	}
+}
 
-	return sessions, nil
+// parseAttachedClients parses the output of list-clients (#{client_session} and #{client_flags})
+// and returns the number of clients attached to each session, except read-only control-mode clients
+// that ignore the size (the clients of tcmux).
+func parseAttachedClients(out string) map[string]int {
+	counts := make(map[string]int)
+	for line := range strings.SplitSeq(strings.TrimRight(out, "\n"), "\n") {
+		session, flags, ok := strings.Cut(line, "\t")
+		if !ok || session == "" {
+			continue
+		}
+		fs := strings.Split(flags, ",")
+		if slices.Contains(fs, "control-mode") && slices.Contains(fs, "read-only") && slices.Contains(fs, "ignore-size") {
+			continue
+		}
+		counts[session]++
+	}
+	return counts
 }
@@ -0,0 +1,61 @@
+package tmux
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCapturePaneArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CapturePaneOptions
+		want []string
+	}{
+		{
+			name: "Visible screen",
+			opts: CapturePaneOptions{},
+			want: []string{"capture-pane", "-p", "-t", "%1"},
+		},
+		{
+			name: "Tail",
+			opts: CapturePaneOptions{Start: "20"},
+			want: []string{"capture-pane", "-p", "-t", "%1", "-S", "20"},
+		},
+		{
+			name: "Whole history with escapes",
+			opts: CapturePaneOptions{Start: "-", End: "-", JoinWrapped: true, Escapes: true},
+			want: []string{"capture-pane", "-p", "-t", "%1", "-S", "-", "-E", "-", "-J", "-e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := capturePaneArgs("%1", tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("capturePaneArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format([]string{"session_name", "pane_id"})
+	want := "#{session_name}\t#{pane_id}"
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestParseAttachedClients(t *testing.T) {
+	out := `dev	attached,focused,UTF-8
+dev	attached,focused,control-mode,ignore-size,no-output,read-only,UTF-8
+dev	attached,focused,control-mode,ignore-size,read-only,UTF-8
+work	attached,focused,control-mode,UTF-8
+work	attached,focused,read-only,UTF-8
+tcmux	attached,focused,control-mode,ignore-size,read-only,UTF-8
+`
+	want := map[string]int{"dev": 1, "work": 2}
+	if got := parseAttachedClients(out); !maps.Equal(got, want) {
+		t.Errorf("parseAttachedClients() = %v, want %v", got, want)
+	}
+}
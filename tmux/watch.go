@@ -0,0 +1,293 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeKind is the kind of a change in tmux.
+type ChangeKind string
+
+// Kinds of changes
+const (
+	ChangeOutput   ChangeKind = "output"   // A pane wrote output (%output)
+	ChangeTitle    ChangeKind = "title"    // The title of a pane changed
+	ChangeWindows  ChangeKind = "windows"  // Windows or panes were added, closed or renamed (%window-add, %window-close, %layout-change, etc.)
+	ChangeSessions ChangeKind = "sessions" // Sessions were created, closed or renamed (%sessions-changed, %session-renamed, etc.)
+)
+
+// Change is a change in tmux.
+type Change struct {
+	Kind   ChangeKind
+	Socket string // Socket path of the tmux server of the pane (empty for changes other than output and title)
+	PaneID string // Pane of output and title changes (empty for the others)
+}
+
+// maxDebounceFactor is the maximum delay of a change in multiples of the debounce interval,
+// so panes that write output continuously (e.g., spinners) are still reported.
+const maxDebounceFactor = 5
+
+// Subscription to the titles of all panes in the session of a control-mode client
+const (
+	titleSubscriptionName = "title"
+	titleSubscription     = titleSubscriptionName + ":%*:#{pane_title}"
+)
+
+// Watcher watches tmux for changes through control-mode clients attached to each session of
+// each tmux server (see SetSockets), since tmux only notifies a control-mode client of the panes in its session.
+// The clients are read-only and do not resize the sessions, but tmux counts them as attached
+// (e.g., in #{session_attached} and #{session_last_attached}); ListSessions does not count them.
+type Watcher struct {
+	changes  chan []Change
+	wake     chan struct{} // Signaled when a change is queued
+	debounce time.Duration
+
+	queueMu sync.Mutex
+	queue   []Change        // Queued changes in order, without duplicates
+	queued  map[Change]bool // Changes in the queue
+	first   time.Time       // When the first change in the queue was queued
+
+	mu      sync.Mutex
+	clients map[sessionKey]*controlClient // Control-mode clients by session
+	titles  map[string]string             // Last titles of panes by PaneKey
+	err     error
+}
+
+// sessionKey identifies a session across tmux servers.
+type sessionKey struct {
+	socket string
+	id     string
+}
+
+// Watch starts watching tmux for changes until the context is canceled or all the tmux servers exit.
+// Changes are debounced: they are published in batches (without duplicates) once no change
+// has been seen for the debounce interval, or at most five times the interval after the first change.
+func Watch(ctx context.Context, debounce time.Duration) (*Watcher, error) {
+	w := &Watcher{
+		changes:  make(chan []Change),
+		wake:     make(chan struct{}, 1),
+		debounce: debounce,
+		clients:  make(map[sessionKey]*controlClient),
+		titles:   make(map[string]string),
+	}
+	if err := w.attach(ctx); err != nil {
+		w.stop()
+		return nil, err
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+// Changes returns the channel of the batches of changes.
+// It is closed when watching ends; Err then returns the reason.
+func (w *Watcher) Changes() <-chan []Change {
+	return w.changes
+}
+
+// Err returns the reason why watching ended (nil if the context was canceled).
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// run debounces the changes from the control-mode clients and publishes them.
+func (w *Watcher) run(ctx context.Context) {
+	defer func() {
+		w.stop()
+		close(w.changes)
+	}()
+
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+			// Wait for the burst to settle, up to the maximum delay
+			w.queueMu.Lock()
+			first := w.first
+			w.queueMu.Unlock()
+			timer.Reset(w.delay(first))
+		case <-timer.C:
+			pending, seen := w.take()
+			if len(pending) == 0 {
+				continue
+			}
+			if seen[Change{Kind: ChangeSessions}] {
+				// Attach to new sessions; end if the tmux server is gone
+				if err := w.attach(ctx); err != nil {
+					w.mu.Lock()
+					w.err = err
+					w.mu.Unlock()
+					return
+				}
+			}
+			// Changes seen while publishing are queued for the next batch
+			select {
+			case w.changes <- pending:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// delay returns the delay until the pending changes since first are published.
+func (w *Watcher) delay(first time.Time) time.Duration {
+	return min(w.debounce, time.Until(first.Add(maxDebounceFactor*w.debounce)))
+}
+
+// attach attaches control-mode clients to the sessions that are not watched yet,
+// and forgets the clients that have exited.
+func (w *Watcher) attach(ctx context.Context) error {
+	w.mu.Lock()
+	for id, c := range w.clients {
+		select {
+		case <-c.done:
+			delete(w.clients, id)
+		default:
+		}
+	}
+	w.mu.Unlock()
+
+	var errs []error
+	for _, socket := range Sockets() {
+		if err := w.attachServer(ctx, socket); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(Sockets()) {
+		return errs[0]
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.clients) == 0 {
+		return errors.New("no tmux sessions to watch")
+	}
+	return nil
+}
+
+// attachServer attaches control-mode clients to the sessions of the server of the socket that are not watched yet.
+func (w *Watcher) attachServer(ctx context.Context, socket string) error {
+	out, err := query(ctx, socket, "list-sessions", "-F", "#{session_id}")
+	if err != nil {
+		return fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	for id := range strings.FieldsSeq(string(out)) {
+		key := sessionKey{socket: socket, id: id}
+		w.mu.Lock()
+		_, ok := w.clients[key]
+		w.mu.Unlock()
+		if ok {
+			continue
+		}
+		c, err := newControlClient(ctx, socket, id, "read-only,ignore-size", func(line string) {
+			w.notify(socket, line)
+		})
+		if err != nil {
+			// The session may have been closed in the meantime
+			continue
+		}
+		if _, err := c.run(ctx, "refresh-client", "-B", titleSubscription); err != nil {
+			c.stop()
+			continue
+		}
+		go func() {
+			// Look for the sessions again when the client exits (e.g., its session is closed)
+			<-c.done
+			w.send(Change{Kind: ChangeSessions})
+		}()
+		w.mu.Lock()
+		w.clients[key] = c
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// stop stops all control-mode clients.
+func (w *Watcher) stop() {
+	w.mu.Lock()
+	clients := w.clients
+	w.clients = make(map[sessionKey]*controlClient)
+	w.mu.Unlock()
+	for _, c := range clients {
+		c.stop()
+	}
+}
+
+// notify converts a control-mode notification from the server of the socket to a change.
+func (w *Watcher) notify(socket, line string) {
+	if c, ok := w.parseNotification(socket, line); ok {
+		w.send(c)
+	}
+}
+
+// send queues the change, unless it is already queued, and wakes up run.
+func (w *Watcher) send(c Change) {
+	w.queueMu.Lock()
+	if !w.queued[c] {
+		if len(w.queue) == 0 {
+			w.first = time.Now()
+		}
+		if w.queued == nil {
+			w.queued = make(map[Change]bool)
+		}
+		w.queued[c] = true
+		w.queue = append(w.queue, c)
+	}
+	w.queueMu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+		// run is already woken up
+	}
+}
+
+// take takes the queued changes.
+func (w *Watcher) take() ([]Change, map[Change]bool) {
+	w.queueMu.Lock()
+	defer w.queueMu.Unlock()
+	queue, queued := w.queue, w.queued
+	w.queue, w.queued = nil, nil
+	return queue, queued
+}
+
+// parseNotification converts a control-mode notification from the server of the socket to a change.
+func (w *Watcher) parseNotification(socket, line string) (Change, bool) {
+	name, rest, _ := strings.Cut(line, " ")
+	switch name {
+	case "%output":
+		paneID, _, _ := strings.Cut(rest, " ")
+		return Change{Kind: ChangeOutput, Socket: socket, PaneID: paneID}, true
+	case "%window-add", "%window-close", "%unlinked-window-add", "%unlinked-window-close", "%window-renamed", "%layout-change":
+		return Change{Kind: ChangeWindows}, true
+	case "%sessions-changed", "%session-renamed":
+		return Change{Kind: ChangeSessions}, true
+	case "%subscription-changed":
+		// %subscription-changed <name> $<session> @<window> <index> %<pane> : <value>
+		fields := strings.Fields(rest)
+		_, value, _ := strings.Cut(rest, " : ")
+		if len(fields) < 5 || fields[0] != titleSubscriptionName {
+			return Change{}, false
+		}
+		paneID := fields[4]
+		key := PaneKey(socket, paneID)
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		last, known := w.titles[key]
+		w.titles[key] = value
+		// The first value is the current title, not a change
+		if !known || last == value {
+			return Change{}, false
+		}
+		return Change{Kind: ChangeTitle, Socket: socket, PaneID: paneID}, true
+	}
+	return Change{}, false
+}
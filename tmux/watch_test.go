@@ -0,0 +1,118 @@
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseNotification(t *testing.T) {
+	w := &Watcher{titles: make(map[string]string)}
+	tests := []struct {
+		socket string
+		line   string
+		want   Change
+		wantOK bool
+	}{
+		{"/tmp/tmux-1000/default", `%output %3 \033[?2004hroot@vm:~# `, Change{Kind: ChangeOutput, Socket: "/tmp/tmux-1000/default", PaneID: "%3"}, true},
+		{"/tmp/tmux-1000/default", "%window-add @5", Change{Kind: ChangeWindows}, true},
+		{"/tmp/tmux-1000/default", "%window-close @5", Change{Kind: ChangeWindows}, true},
+		{"/tmp/tmux-1000/default", "%layout-change @1 b25d,80x24,0,0,1 b25d,80x24,0,0,1 *", Change{Kind: ChangeWindows}, true},
+		{"/tmp/tmux-1000/default", "%sessions-changed", Change{Kind: ChangeSessions}, true},
+		{"/tmp/tmux-1000/default", "%session-changed $1 dev", Change{}, false},
+		// The first title is the current one
+		{"/tmp/tmux-1000/default", "%subscription-changed title $1 @1 0 %1 : ✳ Claude Code", Change{}, false},
+		{"/tmp/tmux-1000/default", "%subscription-changed title $1 @1 0 %1 : ✳ Claude Code", Change{}, false},
+		{"/tmp/tmux-1000/default", "%subscription-changed title $1 @1 0 %1 : ✳ Fix login bug", Change{Kind: ChangeTitle, Socket: "/tmp/tmux-1000/default", PaneID: "%1"}, true},
+		{"/tmp/tmux-1000/default", "%subscription-changed other $1 @1 0 %1 : x", Change{}, false},
+		// Panes of other servers are distinct
+		{"/tmp/tmux-1000/work", "%subscription-changed title $1 @1 0 %1 : ✳ Fix login bug", Change{}, false},
+		{"/tmp/tmux-1000/work", "%output %1 x", Change{Kind: ChangeOutput, Socket: "/tmp/tmux-1000/work", PaneID: "%1"}, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := w.parseNotification(tt.socket, tt.line)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseNotification(%q, %q) = %v, %v, want %v, %v", tt.socket, tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestWatcherDebounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &Watcher{
+		changes:  make(chan []Change),
+		wake:     make(chan struct{}, 1),
+		debounce: 20 * time.Millisecond,
+		clients:  make(map[sessionKey]*controlClient),
+	}
+	go w.run(ctx)
+
+	w.send(Change{Kind: ChangeOutput, PaneID: "%1"})
+	w.send(Change{Kind: ChangeOutput, PaneID: "%2"})
+	w.send(Change{Kind: ChangeOutput, PaneID: "%1"})
+	want := []Change{{Kind: ChangeOutput, PaneID: "%1"}, {Kind: ChangeOutput, PaneID: "%2"}}
+	select {
+	case got := <-w.Changes():
+		if !slices.Equal(got, want) {
+			t.Errorf("Changes() = %v, want %v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no changes published")
+	}
+
+	// Continuous output is published within the maximum delay
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+				w.send(Change{Kind: ChangeOutput, PaneID: "%1"})
+			}
+		}
+	}()
+	defer close(done)
+	select {
+	case <-w.Changes():
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("changes published after %s", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no changes published during continuous output")
+	}
+}
+
+func TestWatcherDoesNotDropChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &Watcher{
+		changes:  make(chan []Change),
+		wake:     make(chan struct{}, 1),
+		debounce: 10 * time.Millisecond,
+		clients:  make(map[sessionKey]*controlClient),
+	}
+	go w.run(ctx)
+
+	// Queue more changes than a bounded queue would hold, while the batches are not consumed
+	const n = 5000
+	for i := range n {
+		w.send(Change{Kind: ChangeOutput, PaneID: fmt.Sprintf("%%%d", i)})
+	}
+	seen := make(map[Change]bool)
+	for len(seen) < n {
+		select {
+		case batch := <-w.Changes():
+			for _, c := range batch {
+				seen[c] = true
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%d of %d changes published", len(seen), n)
+		}
+	}
+}
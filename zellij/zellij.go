@@ -0,0 +1,405 @@
+// Package zellij implements mux.Multiplexer for Zellij with its command-line actions.
+//
+// Zellij only reports the panes focused by its clients (list-clients), and actions from the command line
+// (dump-screen, write) act on the pane focused by the first client of the session (the one with the lowest ID),
+// so the only pane of a session is the pane focused by its first client.
+// Zellij does not report the tab of a pane either, so window variables of panes are empty.
+package zellij
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/tcmux/mux"
+)
+
+// Backend is the Zellij implementation of mux.Multiplexer.
+type Backend struct{}
+
+var _ mux.Multiplexer = Backend{}
+
+// invalidKeyChars matches the characters that cannot be used in pane keys.
+var invalidKeyChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// terminalPanePrefix is the prefix of the IDs of terminal panes (as opposed to plugin panes).
+const terminalPanePrefix = "terminal_"
+
+// Name returns "zellij".
+func (Backend) Name() string {
+	return "zellij"
+}
+
+// ListSessions returns the running Zellij sessions with the values of the variables
+// (session_name, session_windows and session_attached).
+func (Backend) ListSessions(ctx context.Context, vars []string) ([]mux.Session, error) {
+	names, err := listSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []mux.Session
+	err = eachSession(names, func(name string) error {
+		tabs, err := queryTabNames(ctx, name)
+		if err != nil {
+			return err
+		}
+		clients, err := listClients(ctx, name)
+		if err != nil {
+			return err
+		}
+		varMap := newVarMap(vars)
+		varMap["session_name"] = name
+		varMap["session_windows"] = strconv.Itoa(len(tabs))
+		varMap["session_attached"] = strconv.Itoa(len(clients))
+		sessions = append(sessions, mux.Session{Vars: varMap})
+		return nil
+	})
+	return sessions, err
+}
+
+// ListWindows returns the tabs of the Zellij sessions with the values of the variables
+// (session_name, window_index and window_name).
+func (Backend) ListWindows(ctx context.Context, vars []string, opts mux.ListOptions) ([]mux.Window, error) {
+	names, err := targetSessions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	var windows []mux.Window
+	err = eachSession(names, func(name string) error {
+		tabs, err := queryTabNames(ctx, name)
+		if err != nil {
+			return err
+		}
+		for i, tab := range tabs {
+			varMap := newVarMap(vars)
+			varMap["session_name"] = name
+			varMap["window_index"] = strconv.Itoa(i + 1)
+			varMap["window_name"] = tab
+			windows = append(windows, mux.Window{Vars: varMap})
+		}
+		return nil
+	})
+	return windows, err
+}
+
+// ListPanes returns the terminal panes focused by the first clients of the Zellij sessions with the values of
+// the variables (session_name, pane_id, pane_current_command and mux.VarPaneCommand).
+func (Backend) ListPanes(ctx context.Context, vars []string, opts mux.ListOptions) ([]mux.Pane, error) {
+	names, err := targetSessions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	var panes []mux.Pane
+	err = eachSession(names, func(name string) error {
+		clients, err := listClients(ctx, name)
+		if err != nil {
+			return err
+		}
+		c, ok := firstClient(clients)
+		if !ok || !strings.HasPrefix(c.PaneID, terminalPanePrefix) {
+			return nil
+		}
+		varMap := newVarMap(vars)
+		varMap["session_name"] = name
+		varMap["pane_id"] = c.PaneID
+		varMap[mux.VarPaneCommand] = c.Command
+		if fields := strings.Fields(c.Command); len(fields) > 0 {
+			varMap["pane_current_command"] = filepath.Base(fields[0])
+		}
+		panes = append(panes, mux.Pane{Vars: varMap})
+		return nil
+	})
+	return panes, err
+}
+
+// CapturePane dumps the screen of the pane with dump-screen.
+// Returns an error if the pane is not focused by the first client of its session (see ListPanes).
+// Escapes and JoinWrapped are not supported.
+func (Backend) CapturePane(ctx context.Context, pane mux.Pane, opts mux.CaptureOptions) (string, error) {
+	if err := checkTargetPane(ctx, pane); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "tcmux-zellij-*.txt")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	_ = f.Close()
+	defer func() {
+		_ = os.Remove(path)
+	}()
+
+	// Lines in the history ("-" or negative) need the full scrollback
+	full := strings.HasPrefix(opts.Start, "-")
+	args := []string{"dump-screen", path}
+	if full {
+		args = append(args, "--full")
+	}
+	if _, err := action(ctx, pane.Vars["session_name"], args...); err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if full {
+		return string(b), nil
+	}
+	return sliceLines(string(b), opts.Start, opts.End), nil
+}
+
+// FocusPane does nothing for the panes of the current session, since the listed panes are focused by clients.
+// Zellij cannot switch the current client to another session from the command line.
+func (Backend) FocusPane(ctx context.Context, pane mux.Pane) error {
+	if session := pane.Vars["session_name"]; session != os.Getenv("ZELLIJ_SESSION_NAME") {
+		return fmt.Errorf("cannot switch to Zellij session %s from the command line", session)
+	}
+	return nil
+}
+
+// SendKeys writes the keys to the pane.
+// Returns an error if the pane is not focused by the first client of its session (see ListPanes).
+// Keys are key names (Enter, Escape, Tab, BSpace, Space and C-a to C-z) or text.
+func (Backend) SendKeys(ctx context.Context, pane mux.Pane, keys ...string) error {
+	if err := checkTargetPane(ctx, pane); err != nil {
+		return err
+	}
+	session := pane.Vars["session_name"]
+	for _, key := range keys {
+		var args []string
+		if b, ok := keyBytes(key); ok {
+			args = []string{"write"}
+			for _, c := range b {
+				args = append(args, strconv.Itoa(int(c)))
+			}
+		} else {
+			args = []string{"write-chars", key}
+		}
+		if _, err := action(ctx, session, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PaneKey returns the key of the pane (see PaneKey).
+func (Backend) PaneKey(pane mux.Pane) string {
+	return PaneKey(pane.Vars["session_name"], pane.Vars["pane_id"])
+}
+
+// PaneKey returns the key of the pane in the session for records of panes (e.g., "terminal_1@dev"),
+// since pane IDs are only unique within a session.
+func PaneKey(session, paneID string) string {
+	return paneID + "@" + invalidKeyChars.ReplaceAllString(session, "_")
+}
+
+// CurrentPaneKey returns the key of the current Zellij pane ($ZELLIJ_PANE_ID in $ZELLIJ_SESSION_NAME).
+// Returns "" if not running in Zellij.
+func CurrentPaneKey() string {
+	id, session := os.Getenv("ZELLIJ_PANE_ID"), os.Getenv("ZELLIJ_SESSION_NAME")
+	if id == "" || session == "" {
+		return ""
+	}
+	return PaneKey(session, terminalPanePrefix+id)
+}
+
+// client is a client of a Zellij session.
+type client struct {
+	ID      string
+	PaneID  string // Focused pane (e.g., terminal_1 or plugin_2)
+	Command string // Running command of the pane (empty if unknown)
+}
+
+// listSessions returns the names of the running Zellij sessions.
+func listSessions(ctx context.Context) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "zellij", "list-sessions", "--short", "--no-formatting").Output()
+	if err != nil {
+		return nil, fmt.Errorf("zellij list-sessions: %w", err)
+	}
+	return lines(string(out)), nil
+}
+
+// targetSessions returns the names of the sessions to list windows or panes from:
+// all sessions, the target session, or the current session ($ZELLIJ_SESSION_NAME; all sessions outside Zellij).
+func targetSessions(ctx context.Context, opts mux.ListOptions) ([]string, error) {
+	switch {
+	case opts.AllSessions:
+		return listSessions(ctx)
+	case opts.Target != "":
+		return []string{opts.Target}, nil
+	case os.Getenv("ZELLIJ_SESSION_NAME") != "":
+		return []string{os.Getenv("ZELLIJ_SESSION_NAME")}, nil
+	default:
+		return listSessions(ctx)
+	}
+}
+
+// eachSession calls fn for each session. Sessions that fail (e.g., exited in the meantime) are skipped;
+// returns the first error if all sessions fail.
+func eachSession(names []string, fn func(name string) error) error {
+	var errs []error
+	for _, name := range names {
+		if err := fn(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(names) {
+		return errs[0]
+	}
+	return nil
+}
+
+// queryTabNames returns the names of the tabs of the session.
+func queryTabNames(ctx context.Context, session string) ([]string, error) {
+	out, err := action(ctx, session, "query-tab-names")
+	if err != nil {
+		return nil, err
+	}
+	return lines(string(out)), nil
+}
+
+// listClients returns the clients of the session.
+func listClients(ctx context.Context, session string) ([]client, error) {
+	out, err := action(ctx, session, "list-clients")
+	if err != nil {
+		return nil, err
+	}
+	return parseClients(string(out)), nil
+}
+
+// firstClient returns the client with the lowest ID, whose focused pane command-line actions act on.
+// Returns false if there are no clients.
+func firstClient(clients []client) (client, bool) {
+	var (
+		first   client
+		firstID int
+		found   bool
+	)
+	for _, c := range clients {
+		id, err := strconv.Atoi(c.ID)
+		if err != nil {
+			continue
+		}
+		if !found || id < firstID {
+			first, firstID, found = c, id, true
+		}
+	}
+	return first, found
+}
+
+// checkTargetPane returns an error if the pane is not the pane that command-line actions act on in its session,
+// since they cannot target other panes.
+func checkTargetPane(ctx context.Context, pane mux.Pane) error {
+	session := pane.Vars["session_name"]
+	clients, err := listClients(ctx, session)
+	if err != nil {
+		return err
+	}
+	if c, ok := firstClient(clients); !ok || c.PaneID != pane.Vars["pane_id"] {
+		return fmt.Errorf("zellij pane %s in session %s is not focused by the first client of the session", pane.Vars["pane_id"], session)
+	}
+	return nil
+}
+
+// parseClients parses the output of list-clients:
+//
+//	CLIENT_ID ZELLIJ_PANE_ID RUNNING_COMMAND
+//	1         terminal_1     claude --resume
+func parseClients(out string) []client {
+	var clients []client
+	for i, line := range lines(out) {
+		fields := strings.Fields(line)
+		if i == 0 && len(fields) > 0 && fields[0] == "CLIENT_ID" {
+			continue
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		c := client{ID: fields[0], PaneID: fields[1]}
+		if cmd := strings.Join(fields[2:], " "); cmd != "N/A" {
+			c.Command = cmd
+		}
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// action runs a zellij action in the session.
+func action(ctx context.Context, session string, args ...string) ([]byte, error) {
+	cmdArgs := append([]string{"--session", session, "action"}, args...)
+	out, err := exec.CommandContext(ctx, "zellij", cmdArgs...).Output()
+	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("zellij %s: %w: %s", args[0], err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("zellij %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// keyBytes returns the bytes of the key name, or false if it is text.
+func keyBytes(key string) ([]byte, bool) {
+	switch key {
+	case "Enter":
+		return []byte{'\r'}, true
+	case "Escape":
+		return []byte{0x1b}, true
+	case "Tab":
+		return []byte{'\t'}, true
+	case "BSpace":
+		return []byte{0x7f}, true
+	case "Space":
+		return []byte{' '}, true
+	}
+	if len(key) == 3 && strings.HasPrefix(key, "C-") {
+		if c := key[2] | 0x20; c >= 'a' && c <= 'z' {
+			return []byte{c & 0x1f}, true
+		}
+	}
+	return nil, false
+}
+
+// sliceLines returns the lines from start to end (line numbers, or empty or "-" for the first and last lines).
+func sliceLines(content, start, end string) string {
+	ls := strings.SplitAfter(content, "\n")
+	if len(ls) > 0 && ls[len(ls)-1] == "" {
+		ls = ls[:len(ls)-1]
+	}
+	first, last := 0, len(ls)
+	if n, err := strconv.Atoi(start); err == nil {
+		first = min(max(n, 0), len(ls))
+	}
+	if n, err := strconv.Atoi(end); err == nil {
+		last = min(max(n+1, first), len(ls))
+	}
+	return strings.Join(ls[first:last], "")
+}
+
+// lines returns the non-empty lines of the output.
+func lines(out string) []string {
+	var ls []string
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			ls = append(ls, line)
+		}
+	}
+	return ls
+}
+
+// newVarMap returns the variables with empty values, since unsupported variables expand to empty strings.
+func newVarMap(vars []string) map[string]string {
+	varMap := make(map[string]string, len(vars))
+	for _, v := range vars {
+		varMap[v] = ""
+	}
+	return varMap
+}
@@ -0,0 +1,135 @@
+package zellij
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestParseClients(t *testing.T) {
+	out := `CLIENT_ID ZELLIJ_PANE_ID RUNNING_COMMAND
+1         terminal_1     claude --resume
+2         plugin_2       N/A
+3         terminal_3     N/A
+`
+	want := []client{
+		{ID: "1", PaneID: "terminal_1", Command: "claude --resume"},
+		{ID: "2", PaneID: "plugin_2"},
+		{ID: "3", PaneID: "terminal_3"},
+	}
+	if got := parseClients(out); !slices.Equal(got, want) {
+		t.Errorf("parseClients() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFirstClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		clients []client
+		want    client
+		wantOK  bool
+	}{
+		{
+			name: "Two clients in one session",
+			clients: []client{
+				{ID: "3", PaneID: "terminal_2", Command: "codex"},
+				{ID: "1", PaneID: "terminal_1", Command: "claude"},
+			},
+			want:   client{ID: "1", PaneID: "terminal_1", Command: "claude"},
+			wantOK: true,
+		},
+		{
+			name:    "First client on a plugin pane",
+			clients: []client{{ID: "1", PaneID: "plugin_2"}, {ID: "2", PaneID: "terminal_1"}},
+			want:    client{ID: "1", PaneID: "plugin_2"},
+			wantOK:  true,
+		},
+		{
+			name:    "No clients",
+			clients: nil,
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := firstClient(tt.clients)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("firstClient() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	tests := []struct {
+		key    string
+		want   []byte
+		wantOK bool
+	}{
+		{"Enter", []byte{'\r'}, true},
+		{"Escape", []byte{0x1b}, true},
+		{"C-c", []byte{0x03}, true},
+		{"C-C", []byte{0x03}, true},
+		{"C-1", nil, false},
+		{"hello", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := keyBytes(tt.key)
+		if !bytes.Equal(got, tt.want) || ok != tt.wantOK {
+			t.Errorf("keyBytes(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSliceLines(t *testing.T) {
+	content := "a\nb\nc\nd\n"
+	tests := []struct {
+		start string
+		end   string
+		want  string
+	}{
+		{"", "", content},
+		{"2", "", "c\nd\n"},
+		{"1", "2", "b\nc\n"},
+		{"", "-", content},
+		{"10", "", ""},
+		{"3", "1", ""},
+	}
+
+	for _, tt := range tests {
+		if got := sliceLines(content, tt.start, tt.end); got != tt.want {
+			t.Errorf("sliceLines(%q, %q) = %q, want %q", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestPaneKey(t *testing.T) {
+	tests := []struct {
+		session string
+		paneID  string
+		want    string
+	}{
+		{"dev", "terminal_1", "terminal_1@dev"},
+		{"my.project", "terminal_2", "terminal_2@my_project"},
+	}
+
+	for _, tt := range tests {
+		if got := PaneKey(tt.session, tt.paneID); got != tt.want {
+			t.Errorf("PaneKey(%q, %q) = %q, want %q", tt.session, tt.paneID, got, tt.want)
+		}
+	}
+}
+
+func TestCurrentPaneKey(t *testing.T) {
+	t.Setenv("ZELLIJ_SESSION_NAME", "dev")
+	t.Setenv("ZELLIJ_PANE_ID", "4")
+	if got, want := CurrentPaneKey(), "terminal_4@dev"; got != want {
+		t.Errorf("CurrentPaneKey() = %q, want %q", got, want)
+	}
+	t.Setenv("ZELLIJ_PANE_ID", "")
+	if got := CurrentPaneKey(); got != "" {
+		t.Errorf("CurrentPaneKey() outside Zellij = %q, want empty", got)
+	}
+}